@@ -0,0 +1,135 @@
+package arith
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/MichaelZalla/gophercises/01-quiz/quiz"
+)
+
+func TestEval(t *testing.T) {
+
+	testCases := []struct {
+		expr string
+		want float64
+	}{
+		{"5+5", 10},
+		{" 8 + 3 ", 11},
+		{"2+3*4", 14},
+		{"(2+3)*4", 20},
+		{"10-4-3", 3},
+		{"12/4/3", 1},
+		{"-3+5", 2},
+		{"2*(-3)", -6},
+		{"1.5*2", 3},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.expr, func(t *testing.T) {
+
+			got, err := Eval(tc.expr)
+
+			if err != nil {
+				t.Fatalf("Eval() returned an error: %s", err)
+			}
+
+			if got != tc.want {
+				t.Errorf("Got %g, want %g", got, tc.want)
+			}
+
+		})
+	}
+
+}
+
+func TestEvalInvalid(t *testing.T) {
+
+	for _, expr := range []string{"", "5+", "(1+2", "1+2)", "4/0", "two+two", "1..2"} {
+		if _, err := Eval(expr); err == nil {
+			t.Errorf("Expected an error for '%s'", expr)
+		}
+	}
+
+}
+
+func TestGenerate(t *testing.T) {
+
+	opts := Options{Operators: "+-*/", Min: 1, Max: 12, Difficulty: 2, Count: 50, Seed: 3}
+
+	problems, err := Generate(opts)
+
+	if err != nil {
+		t.Fatalf("Generate() returned an error: %s", err)
+	}
+
+	if len(problems) != 50 {
+		t.Fatalf("Got %d problems, want 50", len(problems))
+	}
+
+	if mismatches := Verify(problems); len(mismatches) > 0 {
+		t.Errorf("Expected generated answers to be correct, got %v", mismatches)
+	}
+
+	for _, p := range problems {
+
+		operators := strings.Count(p.Question, "+") + strings.Count(p.Question, "-") +
+			strings.Count(p.Question, "*") + strings.Count(p.Question, "/")
+
+		if operators != opts.Difficulty {
+			t.Errorf("Got %d operators in '%s', want %d", operators, p.Question, opts.Difficulty)
+		}
+
+	}
+
+	again, _ := Generate(opts)
+
+	if !reflect.DeepEqual(problems, again) {
+		t.Errorf("Expected the same seed to generate the same problems")
+	}
+
+}
+
+func TestGenerateInvalid(t *testing.T) {
+
+	for _, opts := range []Options{
+		{Operators: "", Min: 0, Max: 10, Count: 1},
+		{Operators: "^", Min: 0, Max: 10, Count: 1},
+		{Operators: "+", Min: 10, Max: 0, Count: 1},
+		{Operators: "+", Min: 0, Max: 10, Count: -1},
+		{Operators: "+", Min: math.MinInt, Max: math.MaxInt, Count: 1},
+		{Operators: "+", Min: -1, Max: math.MaxInt, Count: 1},
+		{Operators: "+", Min: 0, Max: math.MaxInt, Count: 1},
+	} {
+		if _, err := Generate(opts); err == nil {
+			t.Errorf("Expected an error for %+v", opts)
+		}
+	}
+
+}
+
+func TestVerify(t *testing.T) {
+
+	problems := []quiz.Problem{
+		{Question: "5+5", Answers: []string{"10"}},
+		{Question: "8+3", Answers: []string{"12"}},
+		{Question: "Capital of France", Answers: []string{"Paris"}},
+		{Question: "7/2", Answers: []string{"3.50"}},
+	}
+
+	mismatches := Verify(problems)
+
+	if len(mismatches) != 2 {
+		t.Fatalf("Got %d mismatches, want 2", len(mismatches))
+	}
+
+	if mismatches[0].Number != 2 || mismatches[0].Computed != "11" {
+		t.Errorf("Got %v, want problem #2 to compute to 11", mismatches[0])
+	}
+
+	if mismatches[1].Number != 3 || mismatches[1].Err == nil {
+		t.Errorf("Got %v, want problem #3 to fail to evaluate", mismatches[1])
+	}
+
+}
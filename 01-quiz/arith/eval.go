@@ -0,0 +1,196 @@
+package arith
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Eval computes the value of an arithmetic expression made up of numbers,
+// the operators +, -, * and /, and parentheses. The usual rules of
+// precedence apply.
+func Eval(expr string) (float64, error) {
+
+	p := &parser{input: expr}
+
+	v, err := p.expression()
+
+	if err != nil {
+		return 0, err
+	}
+
+	p.skipSpace()
+
+	if p.pos < len(p.input) {
+		return 0, fmt.Errorf("unexpected '%c' at position %d", p.input[p.pos], p.pos+1)
+	}
+
+	return v, nil
+
+}
+
+// parser is a recursive descent parser for the following grammar:
+//
+//	expression = term { ("+" | "-") term }
+//	term       = factor { ("*" | "/") factor }
+//	factor     = [ "-" ] ( number | "(" expression ")" )
+type parser struct {
+	input string
+	pos   int
+}
+
+func (p *parser) skipSpace() {
+
+	for p.pos < len(p.input) && unicode.IsSpace(rune(p.input[p.pos])) {
+		p.pos++
+	}
+
+}
+
+// peek returns the next non-space character, or 0 at the end of the input.
+func (p *parser) peek() byte {
+
+	p.skipSpace()
+
+	if p.pos >= len(p.input) {
+		return 0
+	}
+
+	return p.input[p.pos]
+
+}
+
+func (p *parser) expression() (float64, error) {
+
+	v, err := p.term()
+
+	if err != nil {
+		return 0, err
+	}
+
+	for {
+
+		op := p.peek()
+
+		if op != '+' && op != '-' {
+			return v, nil
+		}
+
+		p.pos++
+
+		rhs, err := p.term()
+
+		if err != nil {
+			return 0, err
+		}
+
+		if op == '+' {
+			v += rhs
+		} else {
+			v -= rhs
+		}
+
+	}
+
+}
+
+func (p *parser) term() (float64, error) {
+
+	v, err := p.factor()
+
+	if err != nil {
+		return 0, err
+	}
+
+	for {
+
+		op := p.peek()
+
+		if op != '*' && op != '/' {
+			return v, nil
+		}
+
+		p.pos++
+
+		rhs, err := p.factor()
+
+		if err != nil {
+			return 0, err
+		}
+
+		if op == '*' {
+			v *= rhs
+			continue
+		}
+
+		if rhs == 0 {
+			return 0, fmt.Errorf("division by zero at position %d", p.pos)
+		}
+
+		v /= rhs
+
+	}
+
+}
+
+func (p *parser) factor() (float64, error) {
+
+	switch c := p.peek(); {
+
+	case c == '-':
+
+		p.pos++
+
+		v, err := p.factor()
+
+		return -v, err
+
+	case c == '(':
+
+		p.pos++
+
+		v, err := p.expression()
+
+		if err != nil {
+			return 0, err
+		}
+
+		if p.peek() != ')' {
+			return 0, fmt.Errorf("missing ')' at position %d", p.pos+1)
+		}
+
+		p.pos++
+
+		return v, nil
+
+	case c == '.' || (c >= '0' && c <= '9'):
+		return p.number()
+
+	case c == 0:
+		return 0, fmt.Errorf("unexpected end of expression")
+
+	default:
+		return 0, fmt.Errorf("unexpected '%c' at position %d", c, p.pos+1)
+
+	}
+
+}
+
+func (p *parser) number() (float64, error) {
+
+	start := p.pos
+
+	for p.pos < len(p.input) && strings.IndexByte("0123456789.", p.input[p.pos]) >= 0 {
+		p.pos++
+	}
+
+	v, err := strconv.ParseFloat(p.input[start:p.pos], 64)
+
+	if err != nil {
+		return 0, fmt.Errorf("invalid number '%s' at position %d", p.input[start:p.pos], start+1)
+	}
+
+	return v, nil
+
+}
@@ -0,0 +1,187 @@
+package arith
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+
+	"github.com/MichaelZalla/gophercises/01-quiz/quiz"
+)
+
+// Options configures the problems built by Generate.
+type Options struct {
+	// Operators lists the operators to choose from, e.g. "+-*/"
+	Operators string
+	// Min and Max bound each operand (inclusive)
+	Min, Max int
+	// Difficulty is the number of operators in each problem
+	Difficulty int
+	// Count is the number of problems to build
+	Count int
+	// Seed makes the problems reproducible
+	Seed int64
+}
+
+// maxAttempts bounds the search for a problem with a whole-number answer.
+const maxAttempts = 1000
+
+// Generate builds arithmetic problems at random, computing each answer with
+// Eval. Only problems whose answers are whole numbers are kept.
+func Generate(opts Options) ([]quiz.Problem, error) {
+
+	if opts.Operators == "" {
+		return nil, errors.New("no operators to choose from")
+	}
+
+	for _, op := range opts.Operators {
+		if !strings.ContainsRune("+-*/", op) {
+			return nil, fmt.Errorf("unsupported operator '%c'", op)
+		}
+	}
+
+	// The range must also be small enough to count with an int, since
+	// operand picks from Max-Min+1 values
+
+	if span := opts.Max - opts.Min; opts.Min > opts.Max || span < 0 || span == math.MaxInt {
+		return nil, fmt.Errorf("invalid operand range %d to %d", opts.Min, opts.Max)
+	}
+
+	if opts.Count < 0 {
+		return nil, fmt.Errorf("invalid problem count %d", opts.Count)
+	}
+
+	if opts.Difficulty < 1 {
+		opts.Difficulty = 1
+	}
+
+	r := rand.New(rand.NewSource(opts.Seed))
+
+	problems := make([]quiz.Problem, 0, opts.Count)
+
+	for len(problems) < opts.Count {
+
+		p, err := generate(r, opts)
+
+		if err != nil {
+			return nil, err
+		}
+
+		problems = append(problems, p)
+
+	}
+
+	return problems, nil
+
+}
+
+func generate(r *rand.Rand, opts Options) (quiz.Problem, error) {
+
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+
+		var b strings.Builder
+
+		b.WriteString(operand(r, opts))
+
+		for i := 0; i < opts.Difficulty; i++ {
+			b.WriteByte(opts.Operators[r.Intn(len(opts.Operators))])
+			b.WriteString(operand(r, opts))
+		}
+
+		q := b.String()
+
+		v, err := Eval(q)
+
+		if err != nil || v != math.Trunc(v) {
+			continue
+		}
+
+		return quiz.Problem{
+			Question: q,
+			Answers:  []string{format(v)},
+		}, nil
+
+	}
+
+	return quiz.Problem{}, errors.New("failed to generate a problem with a whole-number answer")
+
+}
+
+func operand(r *rand.Rand, opts Options) string {
+
+	n := opts.Min + r.Intn(opts.Max-opts.Min+1)
+
+	if n < 0 {
+		return "(" + strconv.Itoa(n) + ")"
+	}
+
+	return strconv.Itoa(n)
+
+}
+
+func format(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// Mismatch describes a problem whose answer disagrees with its question.
+type Mismatch struct {
+	Number   int
+	Problem  quiz.Problem
+	Computed string
+	Err      error
+}
+
+func (m Mismatch) String() string {
+
+	if m.Err != nil {
+		return fmt.Sprintf("Problem #%d: %s (%s)", m.Number, m.Problem.Question, m.Err)
+	}
+
+	return fmt.Sprintf("Problem #%d: %s = %s, not %s", m.Number, m.Problem.Question, m.Computed, m.Problem.Expected())
+
+}
+
+// Verify evaluates the question of each problem and reports those whose
+// answers are wrong, or whose questions can't be evaluated.
+func Verify(problems []quiz.Problem) []Mismatch {
+
+	var mismatches []Mismatch
+
+	for i, p := range problems {
+
+		v, err := Eval(p.Question)
+
+		if err != nil {
+			mismatches = append(mismatches, Mismatch{Number: i + 1, Problem: p, Err: err})
+			continue
+		}
+
+		computed := format(v)
+
+		if !accepts(p, computed) {
+			mismatches = append(mismatches, Mismatch{Number: i + 1, Problem: p, Computed: computed})
+		}
+
+	}
+
+	return mismatches
+
+}
+
+// accepts reports whether any of the answers to a problem is numerically
+// equal to the computed value.
+func accepts(p quiz.Problem, computed string) bool {
+
+	match := quiz.Numeric(1e-9)
+
+	for _, a := range p.Answers {
+		if match(computed, a) {
+			return true
+		}
+	}
+
+	return false
+
+}
@@ -0,0 +1,285 @@
+package history
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/MichaelZalla/gophercises/01-quiz/quiz"
+)
+
+const day = 24 * time.Hour
+
+// Attempt records a single response to a question.
+type Attempt struct {
+	Time    time.Time     `json:"time"`
+	Correct bool          `json:"correct"`
+	Skipped bool          `json:"skipped,omitempty"`
+	Expired bool          `json:"expired,omitempty"`
+	Latency time.Duration `json:"latency"`
+}
+
+// Card holds every attempt at a question, along with the schedule on which
+// the question should next be reviewed. Scheduling follows the SM-2
+// algorithm: Easiness shrinks each time the question is missed, which in turn
+// shortens the Interval (in days) until it is Due again.
+type Card struct {
+	Quiz        string    `json:"quiz"`
+	Question    string    `json:"question"`
+	Attempts    []Attempt `json:"attempts"`
+	Easiness    float64   `json:"easiness"`
+	Interval    int       `json:"interval"`
+	Repetitions int       `json:"repetitions"`
+	Due         time.Time `json:"due"`
+}
+
+// Store is a file-based record of quiz attempts, keyed by quiz file and
+// question text.
+type Store struct {
+	path  string
+	cards map[string]*Card
+}
+
+// Open reads the store kept at path. A store that does not exist yet is
+// created the first time that it is saved.
+func Open(path string) (*Store, error) {
+
+	s := &Store{
+		path:  path,
+		cards: map[string]*Card{},
+	}
+
+	data, err := ioutil.ReadFile(path)
+
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	var cards []*Card
+
+	if err := json.Unmarshal(data, &cards); err != nil {
+		return nil, err
+	}
+
+	for _, c := range cards {
+		s.cards[key(c.Quiz, c.Question)] = c
+	}
+
+	return s, nil
+
+}
+
+// Save writes the store back to its file.
+func (s *Store) Save() error {
+
+	cards := make([]*Card, 0, len(s.cards))
+
+	for _, c := range s.cards {
+		cards = append(cards, c)
+	}
+
+	sort.Slice(cards, func(i, j int) bool {
+
+		if cards[i].Quiz != cards[j].Quiz {
+			return cards[i].Quiz < cards[j].Quiz
+		}
+
+		return cards[i].Question < cards[j].Question
+
+	})
+
+	data, err := json.MarshalIndent(cards, "", "  ")
+
+	if err != nil {
+		return err
+	}
+
+	// Write to a temporary file first, so that a failed write can't corrupt
+	// the existing history
+
+	tmp := s.path + ".tmp"
+
+	if err := ioutil.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, s.path)
+
+}
+
+// Card returns the history of a question, if it has been attempted before.
+func (s *Store) Card(quizFile, question string) (Card, bool) {
+
+	c, ok := s.cards[key(quizFile, question)]
+
+	if !ok {
+		return Card{}, false
+	}
+
+	return *c, true
+
+}
+
+// Record adds the responses from a quiz session to the store, and
+// reschedules each question that was attempted.
+func (s *Store) Record(quizFile string, result quiz.Result, now time.Time) {
+
+	for _, r := range result.Responses {
+
+		k := key(quizFile, r.Problem.Question)
+
+		c, ok := s.cards[k]
+
+		if !ok {
+			c = &Card{
+				Quiz:     quizFile,
+				Question: r.Problem.Question,
+				Easiness: 2.5,
+			}
+			s.cards[k] = c
+		}
+
+		a := Attempt{
+			Time:    now,
+			Correct: r.Correct,
+			Skipped: r.Skipped,
+			Expired: r.Expired,
+			Latency: r.Elapsed,
+		}
+
+		c.Attempts = append(c.Attempts, a)
+
+		c.schedule(grade(a), now)
+
+	}
+
+}
+
+// Review returns a copy of the quiz holding only the problems that are due
+// for review, the most overdue first. Problems that have never been
+// attempted are always due.
+func (s *Store) Review(quizFile string, q quiz.Quiz, now time.Time) quiz.Quiz {
+
+	type due struct {
+		problem quiz.Problem
+		at      time.Time
+		ease    float64
+	}
+
+	var queue []due
+
+	for _, p := range q.Problems {
+
+		c, ok := s.cards[key(quizFile, p.Question)]
+
+		if !ok {
+			queue = append(queue, due{p, time.Time{}, 0})
+			continue
+		}
+
+		if !c.Due.After(now) {
+			queue = append(queue, due{p, c.Due, c.Easiness})
+		}
+
+	}
+
+	sort.SliceStable(queue, func(i, j int) bool {
+
+		if !queue[i].at.Equal(queue[j].at) {
+			return queue[i].at.Before(queue[j].at)
+		}
+
+		return queue[i].ease < queue[j].ease
+
+	})
+
+	problems := make([]quiz.Problem, len(queue))
+
+	for i, d := range queue {
+		problems[i] = d.problem
+	}
+
+	q.Problems = problems
+
+	return q
+
+}
+
+// grade rates the quality of an attempt on the 0-5 scale used by SM-2.
+func grade(a Attempt) int {
+
+	switch {
+	case a.Skipped || a.Expired:
+		return 0
+	case !a.Correct:
+		return 1
+	case a.Latency > 10*time.Second:
+		return 3
+	case a.Latency > 5*time.Second:
+		return 4
+	default:
+		return 5
+	}
+
+}
+
+// schedule updates a card following an attempt of the given quality.
+func (c *Card) schedule(quality int, now time.Time) {
+
+	if quality < 3 {
+
+		c.Repetitions = 0
+
+		c.Interval = 1
+
+	} else {
+
+		switch c.Repetitions {
+		case 0:
+			c.Interval = 1
+		case 1:
+			c.Interval = 6
+		default:
+			c.Interval = int(float64(c.Interval)*c.Easiness + 0.5)
+		}
+
+		c.Repetitions++
+
+	}
+
+	q := float64(5 - quality)
+
+	c.Easiness += 0.1 - q*(0.08+q*0.02)
+
+	if c.Easiness < 1.3 {
+		c.Easiness = 1.3
+	}
+
+	c.Due = now.Add(time.Duration(c.Interval) * day)
+
+}
+
+// DefaultPath returns the location of the store in the user's home
+// directory, falling back to the working directory.
+func DefaultPath() string {
+
+	home, err := os.UserHomeDir()
+
+	if err != nil {
+		return ".quiz_history.json"
+	}
+
+	return filepath.Join(home, ".quiz_history.json")
+
+}
+
+func key(quizFile, question string) string {
+	return quizFile + "\x00" + question
+}
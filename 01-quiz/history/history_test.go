@@ -0,0 +1,98 @@
+package history
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/MichaelZalla/gophercises/01-quiz/quiz"
+)
+
+var (
+	easy   = quiz.Problem{Question: "1+1", Answers: []string{"2"}}
+	hard   = quiz.Problem{Question: "17*23", Answers: []string{"391"}}
+	unseen = quiz.Problem{Question: "2+2", Answers: []string{"4"}}
+)
+
+func session(correctEasy, correctHard bool) quiz.Result {
+
+	return quiz.Result{Responses: []quiz.Response{
+		{Problem: easy, Correct: correctEasy, Elapsed: time.Second},
+		{Problem: hard, Correct: correctHard, Elapsed: 2 * time.Second},
+	}}
+
+}
+
+func TestRecordAndReopen(t *testing.T) {
+
+	path := filepath.Join(t.TempDir(), "history.json")
+
+	s, err := Open(path)
+
+	if err != nil {
+		t.Fatalf("Open() returned an error: %s", err)
+	}
+
+	now := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	s.Record("problems.csv", session(true, false), now)
+
+	if err := s.Save(); err != nil {
+		t.Fatalf("Save() returned an error: %s", err)
+	}
+
+	s, err = Open(path)
+
+	if err != nil {
+		t.Fatalf("Open() returned an error: %s", err)
+	}
+
+	c, ok := s.Card("problems.csv", hard.Question)
+
+	if !ok {
+		t.Fatalf("Expected a card for '%s'", hard.Question)
+	}
+
+	if len(c.Attempts) != 1 || c.Attempts[0].Correct || c.Attempts[0].Latency != 2*time.Second {
+		t.Errorf("Got %+v, want a single incorrect attempt taking 2s", c.Attempts)
+	}
+
+	if _, ok := s.Card("alternate.csv", hard.Question); ok {
+		t.Errorf("Expected cards to be kept separately for each quiz file")
+	}
+
+}
+
+func TestReviewSchedule(t *testing.T) {
+
+	s, _ := Open(filepath.Join(t.TempDir(), "history.json"))
+
+	now := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	// Answer the easy question correctly every time, and miss the hard one
+
+	for i := 0; i < 3; i++ {
+		s.Record("problems.csv", session(true, false), now)
+		now = now.Add(day)
+	}
+
+	q := quiz.Quiz{Problems: []quiz.Problem{easy, hard, unseen}}
+
+	review := s.Review("problems.csv", q, now)
+
+	if len(review.Problems) != 2 {
+		t.Fatalf("Got %d problems due for review, want 2", len(review.Problems))
+	}
+
+	if review.Problems[0].Question != unseen.Question || review.Problems[1].Question != hard.Question {
+		t.Errorf("Got %s then %s, want the new question and then the missed one", review.Problems[0].Question, review.Problems[1].Question)
+	}
+
+	e, _ := s.Card("problems.csv", easy.Question)
+	h, _ := s.Card("problems.csv", hard.Question)
+
+	if !h.Due.Before(e.Due) || h.Easiness >= e.Easiness {
+		t.Errorf("Expected the missed question (due %s) to come back before the easy one (due %s)", h.Due, e.Due)
+	}
+
+}
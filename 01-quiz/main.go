@@ -1,113 +1,398 @@
 package main
 
 import (
-	"encoding/csv"
+	"bufio"
+	"context"
 	"flag"
 	"fmt"
 	"log"
+	"net"
+	"net/http"
 	"os"
+	"path/filepath"
 	"strings"
 	"time"
+
+	"github.com/MichaelZalla/gophercises/01-quiz/arith"
+	"github.com/MichaelZalla/gophercises/01-quiz/history"
+	"github.com/MichaelZalla/gophercises/01-quiz/quiz"
+	"github.com/MichaelZalla/gophercises/01-quiz/race"
+	"github.com/MichaelZalla/gophercises/01-quiz/report"
+	"github.com/MichaelZalla/gophercises/01-quiz/web"
 )
 
-type problem struct {
-	q string
-	a string
-}
+func main() {
 
-func normalize(input string) string {
-	return strings.TrimSpace(strings.ToLower(input))
-}
+	// Define CLI flags
 
-func getProblems(entries [][]string) []problem {
+	var csvPath = flag.String("csv", "./data/problems.csv", "path to your questions CSV file")
 
-	problems := make([]problem, len(entries))
+	var quizFile = flag.String("file", "", "path to your quiz file (.csv, .json or .yaml); overrides -csv")
 
-	for i, entry := range entries {
-		problems[i] = problem{
-			q: entry[0],
-			a: normalize(entry[1]),
-		}
-	}
+	var timeLimit = flag.Int("limit", 30, "time limit for the quiz (default: 30 seconds).")
 
-	return problems
+	var questionLimit = flag.Int("per-question", 0, "time limit for each question, in seconds (default: none).")
 
-}
+	var match = flag.String("match", "", "how answers are compared: exact, case-insensitive, numeric[:tolerance], levenshtein[:edits] or folded")
 
-func main() {
+	var shuffle = flag.Bool("shuffle", false, "shuffle the order of the questions")
 
-	// Define CLI flags
+	var sampleSize = flag.Int("n", 0, "ask a random sample of n questions (default: all).")
 
-	var filepath = flag.String("csv", "./data/problems.csv", "path to your questions CSV file")
+	var seed = flag.Int64("seed", 0, "seed for -shuffle, -n and -generate, to reproduce a session (default: random).")
 
-	var timeLimit = flag.Int("limit", 30, "time limit for the quiz (default: 30 seconds).")
+	var historyPath = flag.String("history", history.DefaultPath(), "path to the file in which attempts are recorded (empty to disable)")
+
+	var review = flag.Bool("review", false, "only ask the questions that are due for review, based on past attempts")
+
+	var reportPath = flag.String("report", "", "write a detailed report of the results to this file (.json, .csv or JUnit .xml)")
+
+	var generate = flag.Bool("generate", false, "generate arithmetic problems instead of reading a quiz file")
+
+	var operators = flag.String("operators", "+-", "operators used by -generate")
+
+	var minOperand = flag.Int("min", 0, "smallest operand used by -generate")
+
+	var maxOperand = flag.Int("max", 10, "largest operand used by -generate")
+
+	var difficulty = flag.Int("difficulty", 1, "number of operators in each problem built by -generate")
+
+	var count = flag.Int("count", 10, "number of problems built by -generate")
+
+	var verify = flag.Bool("verify", false, "check that the answers in the quiz file are correct, then exit")
+
+	var lint = flag.Bool("lint", false, "check the quiz file for mistakes, then exit (non-zero if any are found)")
+
+	var serve = flag.String("serve", "", "serve the quiz over HTTP on this address (e.g. :8080) instead of the terminal")
+
+	var adminToken = flag.String("admin-token", "", "token required to view the admin page when using -serve, as a bearer token or basic auth password (the page is disabled without one)")
+
+	var host = flag.String("host", "", "host a multiplayer race over TCP on this address (e.g. :9000)")
+
+	var players = flag.Int("players", 0, "start the race once this many players have joined (default: when Enter is pressed)")
+
+	var join = flag.String("join", "", "join a multiplayer race hosted at this address, then exit")
+
+	var tags = flag.String("tags", "", "only ask questions with one of these comma-separated tags or categories")
+
+	var adaptive = flag.Bool("adaptive", false, "pick each question's difficulty based on your accuracy so far")
 
 	// Initialize CLI flags
 
 	flag.Parse()
 
-	// Get a file reader for the CSV file
+	// Decode the quiz file, choosing a format based on its extension
+
+	path := *csvPath
+
+	if *quizFile != "" {
+		path = *quizFile
+	}
+
+	// Join someone else's race, which needs no quiz file of our own
+
+	if *join != "" {
+
+		if err := race.Join(*join, os.Stdin, os.Stdout); err != nil {
+			log.Fatal(err)
+		}
+
+		return
+
+	}
+
+	// Check the quiz file for mistakes, rather than running it
+
+	if *lint {
+
+		diags, err := quiz.Lint(path)
+
+		if err != nil {
+			log.Fatal(fmt.Sprintf("Failed to lint quiz file '%v' (%s).", path, err))
+		}
+
+		for _, d := range diags {
+			fmt.Printf("%s: %s\n", path, d)
+		}
+
+		if len(diags) > 0 {
+			os.Exit(1)
+		}
+
+		fmt.Printf("No mistakes found in %s.\n", path)
+
+		return
+
+	}
+
+	var q quiz.Quiz
+
+	var err error
+
+	if *generate {
+
+		if *seed == 0 {
+			*seed = time.Now().UnixNano()
+			fmt.Printf("Using seed %d.\n", *seed)
+		}
+
+		q.Problems, err = arith.Generate(arith.Options{
+			Operators:  *operators,
+			Min:        *minOperand,
+			Max:        *maxOperand,
+			Difficulty: *difficulty,
+			Count:      *count,
+			Seed:       *seed,
+		})
+
+		if err != nil {
+			log.Fatal(fmt.Sprintf("Failed to generate problems (%s).", err))
+		}
+
+		path = "generated"
+
+	} else {
+
+		q, err = quiz.Load(path)
+
+		if err != nil {
+			log.Fatal(fmt.Sprintf("Failed to read quiz file '%v' (%s).", path, err))
+		}
+
+	}
+
+	// Check the answers in the quiz file, rather than running it
+
+	if *verify {
+
+		mismatches := arith.Verify(q.Problems)
+
+		for _, m := range mismatches {
+			fmt.Println(m)
+		}
+
+		if len(mismatches) > 0 {
+			os.Exit(1)
+		}
+
+		fmt.Printf("All %d answers are correct.\n", len(q.Problems))
+
+		return
+
+	}
+
+	q.TimeLimit = time.Duration(*timeLimit) * time.Second
+
+	q.QuestionLimit = time.Duration(*questionLimit) * time.Second
+
+	if *match != "" {
+
+		m, err := quiz.ParseMatcher(*match)
+
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		q.Match = m
+
+	}
+
+	// Narrow the quiz down to the requested topics
+
+	if *tags != "" {
+
+		q = q.Tagged(strings.Split(*tags, ",")...)
+
+		if len(q.Problems) == 0 {
+			log.Fatal(fmt.Sprintf("No questions in '%v' are tagged with %s.", path, *tags))
+		}
+
+	}
 
-	file, err := os.Open(*filepath)
+	// Open the history of past attempts (generated problems are unlikely to
+	// come up twice, so they aren't worth keeping)
 
-	defer file.Close()
+	var h *history.Store
+
+	if *historyPath != "" && !*generate {
+
+		h, err = history.Open(*historyPath)
+
+		if err != nil {
+			log.Fatal(fmt.Sprintf("Failed to read history file '%v' (%s).", *historyPath, err))
+		}
+
+	}
+
+	quizKey, err := filepath.Abs(path)
 
 	if err != nil {
-		log.Fatal(fmt.Sprintf("Failed to open CSV file '%v' (%s).", *filepath, err))
+		quizKey = path
+	}
+
+	// Pick (and order) the questions to be asked
+
+	if *review {
+
+		if h == nil {
+			log.Fatal("The -review flag requires a -history file.")
+		}
+
+		q = h.Review(quizKey, q, time.Now())
+
+		if len(q.Problems) == 0 {
+			fmt.Println("Nothing is due for review. Come back later!")
+			return
+		}
+
+		if *sampleSize > 0 && *sampleSize < len(q.Problems) {
+			q.Problems = q.Problems[:*sampleSize]
+		}
+
+	} else if *adaptive {
+
+		// Every question is up for grabs, but only -n of them will be asked
+
+		q.Adaptive = true
+
+		q.Ask = *sampleSize
+
+	} else if *shuffle || *sampleSize > 0 {
+
+		if *seed == 0 {
+			*seed = time.Now().UnixNano()
+			fmt.Printf("Using seed %d.\n", *seed)
+		}
+
+		q = q.Sample(*sampleSize, *shuffle, *seed)
+
+	}
+
+	// Serve the quiz to any number of learners over HTTP
+
+	if *serve != "" {
+
+		srv := web.NewServer(filepath.Base(path), q, web.WithAdminToken(*adminToken))
+
+		fmt.Printf("Starting the server on %s\n", *serve)
+
+		log.Fatal(http.ListenAndServe(*serve, srv))
+
 	}
 
-	// Get a CSV reader to decode the CSV data into a native Go structure
+	// Race any number of players over TCP
 
-	reader := csv.NewReader(file)
+	if *host != "" {
+		hostRace(q, *host, *players)
+		return
+	}
 
-	// Scan through each record in the CSV file
+	// Run the quiz in the terminal
 
-	entries, err := reader.ReadAll()
+	result, err := quiz.NewSession(q).Run(context.Background(), os.Stdin, os.Stdout)
 
 	if err != nil {
-		log.Fatal(fmt.Sprintf("Failed to read CSV file '%v' (%s).", &filepath, err))
+		log.Fatal(err)
 	}
 
-	problems := getProblems(entries)
+	// Break the results down question by question
 
-	correct := 0
+	rep := report.New(filepath.Base(path), q, result)
 
-	timer := time.NewTimer(time.Duration(*timeLimit) * time.Second)
+	fmt.Println()
 
-	for i, p := range problems {
+	rep.WriteText(os.Stdout)
 
-		fmt.Printf("Problem #%d: %s = \n", i+1, p.q)
+	fmt.Println()
 
-		answerCh := make(chan string)
+	fmt.Println(result)
 
-		go func() {
+	if categorized(q) {
 
-			var answer string
+		fmt.Println("Accuracy by category:")
 
-			fmt.Scanf("%s\n", &answer)
+		for _, a := range result.ByCategory() {
+			fmt.Printf("  %s\n", a)
+		}
 
-			answerCh <- normalize(answer)
+	}
 
-		}()
+	if *reportPath != "" {
+		if err := rep.Save(*reportPath); err != nil {
+			log.Printf("Failed to write report file '%v' (%s).", *reportPath, err)
+		}
+	}
 
-		select {
+	// Record this attempt in the history
 
-		case <-timer.C:
+	if h != nil {
 
-			fmt.Printf("You scored %d out of %d.\n", correct, len(problems))
+		h.Record(quizKey, result, time.Now())
 
-			return
+		if err := h.Save(); err != nil {
+			log.Printf("Failed to save history file '%v' (%s).", *historyPath, err)
+		}
 
-		case answer := <-answerCh:
+	}
+
+	// Point out where the most time was spent
+
+	if slowest := result.Slowest(3); len(slowest) > 0 {
 
-			if answer == p.a {
-				correct++
-			}
+		fmt.Println("Slowest questions:")
 
+		for _, r := range slowest {
+			fmt.Printf("  %s (%s)\n", r.Problem.Question, r.Elapsed.Round(time.Millisecond))
 		}
 
 	}
 
-	fmt.Printf("You scored %d out of %d.\n", correct, len(problems))
+}
+
+func hostRace(q quiz.Quiz, addr string, players int) {
+
+	ln, err := net.Listen("tcp", addr)
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	defer ln.Close()
+
+	h := race.NewHost(q, os.Stdout)
+
+	go h.Accept(ln)
+
+	ctx := context.Background()
+
+	fmt.Printf("Hosting a race on %s.\n", ln.Addr())
+
+	if players > 0 {
+
+		fmt.Printf("Waiting for %d player(s) to join...\n", players)
+
+		h.WaitFor(ctx, players)
+
+	} else {
+
+		fmt.Println("Press Enter to start the race once everyone has joined.")
+
+		bufio.NewReader(os.Stdin).ReadString('\n')
+
+	}
+
+	h.Run(ctx)
+
+}
+
+func categorized(q quiz.Quiz) bool {
+
+	for _, p := range q.Problems {
+		if p.Category != "" {
+			return true
+		}
+	}
+
+	return false
 
 }
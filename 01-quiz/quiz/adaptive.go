@@ -0,0 +1,59 @@
+package quiz
+
+// adapt returns the position (among the pending problems) of the problem
+// whose difficulty is closest to a target set by the learner's accuracy so
+// far. A learner who has answered everything correctly is given the hardest
+// problems, while one who has answered nothing correctly is given the
+// easiest. Ties go to the problem that comes first in the quiz.
+func (s *Session) adapt() int {
+
+	lowest, highest := s.difficultyRange()
+
+	target := float64(lowest+highest) / 2
+
+	if asked := len(s.result.Responses); asked > 0 {
+		accuracy := float64(s.result.Correct) / float64(asked)
+		target = float64(lowest) + accuracy*float64(highest-lowest)
+	}
+
+	best, bestDistance := 0, -1.0
+
+	for i, j := range s.pending {
+
+		distance := float64(s.quiz.Problems[j].Level()) - target
+
+		if distance < 0 {
+			distance = -distance
+		}
+
+		if bestDistance < 0 || distance < bestDistance {
+			best, bestDistance = i, distance
+		}
+
+	}
+
+	return best
+
+}
+
+func (s *Session) difficultyRange() (int, int) {
+
+	lowest, highest := 0, 0
+
+	for i, p := range s.quiz.Problems {
+
+		level := p.Level()
+
+		if i == 0 || level < lowest {
+			lowest = level
+		}
+
+		if i == 0 || level > highest {
+			highest = level
+		}
+
+	}
+
+	return lowest, highest
+
+}
@@ -0,0 +1,135 @@
+package quiz
+
+import (
+	"strings"
+	"testing"
+)
+
+var leveledProblems = []Problem{
+	{Question: "1+1", Answers: []string{"2"}, Category: "addition", Difficulty: 1},
+	{Question: "12+19", Answers: []string{"31"}, Category: "addition", Difficulty: 2},
+	{Question: "3*4", Answers: []string{"12"}, Category: "multiplication", Tags: []string{"times-tables"}, Difficulty: 2},
+	{Question: "17*23", Answers: []string{"391"}, Category: "multiplication", Difficulty: 3},
+	{Question: "2+2", Answers: []string{"4"}, Category: "addition", Difficulty: 1},
+}
+
+func TestAdaptive(t *testing.T) {
+
+	s := NewSession(Quiz{Problems: leveledProblems, Adaptive: true})
+
+	// With no answers yet, the first problem is of middling difficulty
+
+	p, _, _ := s.Current()
+
+	if p.Difficulty != 2 {
+		t.Fatalf("Got a first problem of difficulty %d, want 2", p.Difficulty)
+	}
+
+	// A correct answer calls for the hardest problem
+
+	s.Answer(p.Expected(), 0)
+
+	if p, _, _ = s.Current(); p.Question != "17*23" {
+		t.Fatalf("Got '%s' after a correct answer, want '17*23'", p.Question)
+	}
+
+	// Getting it wrong (50% accuracy) brings the difficulty back down
+
+	s.Answer("400", 0)
+
+	if p, _, _ = s.Current(); p.Difficulty != 2 {
+		t.Fatalf("Got '%s' after a wrong answer, want a problem of difficulty 2", p.Question)
+	}
+
+	s.Answer("wrong", 0)
+
+	if p, _, _ = s.Current(); p.Difficulty != 1 {
+		t.Fatalf("Got '%s' after two wrong answers, want a problem of difficulty 1", p.Question)
+	}
+
+}
+
+func TestAsk(t *testing.T) {
+
+	s := NewSession(Quiz{Problems: leveledProblems, Adaptive: true, Ask: 2})
+
+	for i := 0; i < 2; i++ {
+
+		if _, _, ok := s.Current(); !ok {
+			t.Fatalf("Expected problem #%d to be asked", i+1)
+		}
+
+		s.Answer("", 0)
+
+	}
+
+	if _, _, ok := s.Current(); ok {
+		t.Errorf("Expected the session to end after two problems")
+	}
+
+	if r := s.Result(); r.Total != 2 || r.MaxScore != 2 {
+		t.Errorf("Got a total of %d and %g points, want 2 and 2", r.Total, r.MaxScore)
+	}
+
+}
+
+func TestTagged(t *testing.T) {
+
+	q := Quiz{Problems: leveledProblems}
+
+	if got := q.Tagged("Multiplication"); len(got.Problems) != 2 {
+		t.Errorf("Got %d problems in the multiplication category, want 2", len(got.Problems))
+	}
+
+	if got := q.Tagged("times-tables", "nothing"); len(got.Problems) != 1 || got.Problems[0].Question != "3*4" {
+		t.Errorf("Got %v, want only '3*4'", questions(got))
+	}
+
+}
+
+func TestByCategory(t *testing.T) {
+
+	r := Result{Responses: []Response{
+		{Problem: leveledProblems[0], Correct: true},
+		{Problem: leveledProblems[1], Correct: false},
+		{Problem: leveledProblems[2], Correct: true},
+		{Problem: Problem{Question: "?"}, Correct: true},
+	}}
+
+	got := r.ByCategory()
+
+	want := []Accuracy{
+		{"addition", 2, 1},
+		{"multiplication", 1, 1},
+		{"uncategorized", 1, 1},
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("Got %v, want %v", got, want)
+	}
+
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Got %v, want %v", got[i], want[i])
+		}
+	}
+
+	if s := got[0].String(); s != "addition: 1 out of 2 (50%)" {
+		t.Errorf("Got %q", s)
+	}
+
+}
+
+func TestReadCSVCategories(t *testing.T) {
+
+	problems, err := ReadCSV(strings.NewReader("5+5,10,,addition,2\n3*4,12\n"))
+
+	if err != nil {
+		t.Fatalf("ReadCSV() returned an error: %s", err)
+	}
+
+	if problems[0].Category != "addition" || problems[0].Difficulty != 2 || problems[1].Level() != 1 {
+		t.Errorf("Got %+v, want a category and difficulty on the first problem only", problems)
+	}
+
+}
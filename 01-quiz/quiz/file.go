@@ -0,0 +1,185 @@
+package quiz
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+// quizFile is the shape of a quiz written as JSON or YAML, e.g.
+//
+//	match: folded
+//	problems:
+//	  - question: What is the capital of France?
+//	    answers: [Paris]
+//	    choices: [Berlin, Paris, Madrid]
+//	    points: 2
+//	    explanation: Paris has been the capital since 987.
+//	    time_limit: 10
+//	    match: levenshtein:1
+//	    category: geography
+//	    tags: [europe, capitals]
+//	    difficulty: 2
+//
+// See ParseMatcher for the ways in which answers may be matched.
+type quizFile struct {
+	Match    string         `json:"match" yaml:"match"`
+	Problems []problemEntry `json:"problems" yaml:"problems"`
+}
+
+type problemEntry struct {
+	Question    string   `json:"question" yaml:"question"`
+	Answers     []string `json:"answers" yaml:"answers"`
+	Choices     []string `json:"choices" yaml:"choices"`
+	Points      float64  `json:"points" yaml:"points"`
+	Explanation string   `json:"explanation" yaml:"explanation"`
+	TimeLimit   float64  `json:"time_limit" yaml:"time_limit"`
+	Match       string   `json:"match" yaml:"match"`
+	Category    string   `json:"category" yaml:"category"`
+	Tags        []string `json:"tags" yaml:"tags"`
+	Difficulty  int      `json:"difficulty" yaml:"difficulty"`
+}
+
+// Load reads a quiz from the file at path. The format of the file is chosen
+// by its extension: .json, .yaml (or .yml) and .csv are understood.
+func Load(path string) (Quiz, error) {
+
+	file, err := os.Open(path)
+
+	if err != nil {
+		return Quiz{}, err
+	}
+
+	defer file.Close()
+
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+
+	case ".csv":
+
+		problems, err := ReadCSV(file)
+
+		if err != nil {
+			return Quiz{}, err
+		}
+
+		return Quiz{Problems: problems}, nil
+
+	case ".json":
+		return ReadJSON(file)
+
+	case ".yaml", ".yml":
+		return ReadYAML(file)
+
+	default:
+		return Quiz{}, fmt.Errorf("unsupported quiz file extension '%s'", ext)
+
+	}
+
+}
+
+// ReadJSON decodes a quiz written as JSON.
+func ReadJSON(r io.Reader) (Quiz, error) {
+
+	var f quizFile
+
+	d := json.NewDecoder(r)
+
+	d.DisallowUnknownFields()
+
+	if err := d.Decode(&f); err != nil {
+		return Quiz{}, err
+	}
+
+	return f.quiz()
+
+}
+
+// ReadYAML decodes a quiz written as YAML.
+func ReadYAML(r io.Reader) (Quiz, error) {
+
+	data, err := ioutil.ReadAll(r)
+
+	if err != nil {
+		return Quiz{}, err
+	}
+
+	var f quizFile
+
+	if err := yaml.UnmarshalStrict(data, &f); err != nil {
+		return Quiz{}, err
+	}
+
+	return f.quiz()
+
+}
+
+func (f quizFile) quiz() (Quiz, error) {
+
+	q := Quiz{}
+
+	if f.Match != "" {
+
+		match, err := ParseMatcher(f.Match)
+
+		if err != nil {
+			return Quiz{}, err
+		}
+
+		q.Match = match
+
+	}
+
+	problems := make([]Problem, len(f.Problems))
+
+	for i, e := range f.Problems {
+
+		if strings.TrimSpace(e.Question) == "" {
+			return Quiz{}, fmt.Errorf("problem %d: missing question", i+1)
+		}
+
+		if len(e.Answers) == 0 {
+			return Quiz{}, fmt.Errorf("problem %d: missing answers", i+1)
+		}
+
+		if e.Points < 0 || e.TimeLimit < 0 || e.Difficulty < 0 {
+			return Quiz{}, fmt.Errorf("problem %d: points, time limit and difficulty may not be negative", i+1)
+		}
+
+		problems[i] = Problem{
+			Question:    e.Question,
+			Answers:     e.Answers,
+			Choices:     e.Choices,
+			Points:      e.Points,
+			Explanation: e.Explanation,
+			TimeLimit:   time.Duration(e.TimeLimit * float64(time.Second)),
+			Category:    e.Category,
+			Tags:        e.Tags,
+			Difficulty:  e.Difficulty,
+		}
+
+		if e.Match != "" {
+
+			match, err := ParseMatcher(e.Match)
+
+			if err != nil {
+				return Quiz{}, fmt.Errorf("problem %d: %s", i+1, err)
+			}
+
+			problems[i].Match = match
+
+		}
+
+	}
+
+	q.Problems = problems
+
+	return q, nil
+
+}
@@ -0,0 +1,153 @@
+package quiz
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testYAML = `
+problems:
+  - question: What is the capital of France?
+    answers: [Paris]
+    choices: [Berlin, Paris, Madrid]
+    points: 2
+    explanation: Paris has been the capital since 987.
+  - question: Name a primary colour
+    answers: [red, yellow, blue]
+    time_limit: 7.5
+`
+
+const testJSON = `{
+  "problems": [
+    {
+      "question": "What is the capital of France?",
+      "answers": ["Paris"],
+      "choices": ["Berlin", "Paris", "Madrid"],
+      "points": 2,
+      "explanation": "Paris has been the capital since 987."
+    },
+    {
+      "question": "Name a primary colour",
+      "answers": ["red", "yellow", "blue"],
+      "time_limit": 7.5
+    }
+  ]
+}`
+
+func checkQuizFile(t *testing.T, q Quiz) {
+
+	t.Helper()
+
+	if len(q.Problems) != 2 {
+		t.Fatalf("Got %d problems, want 2", len(q.Problems))
+	}
+
+	first, second := q.Problems[0], q.Problems[1]
+
+	if len(first.Choices) != 3 || first.Weight() != 2 || first.Explanation == "" {
+		t.Errorf("Got %+v, want a weighted multiple choice problem with an explanation", first)
+	}
+
+	if len(second.Answers) != 3 || second.Weight() != 1 || second.TimeLimit != 7500*time.Millisecond {
+		t.Errorf("Got %+v, want three answers, a weight of 1 and a 7.5s limit", second)
+	}
+
+}
+
+func TestReadYAML(t *testing.T) {
+
+	q, err := ReadYAML(strings.NewReader(testYAML))
+
+	if err != nil {
+		t.Fatalf("ReadYAML() returned an error: %s", err)
+	}
+
+	checkQuizFile(t, q)
+
+}
+
+func TestReadJSON(t *testing.T) {
+
+	q, err := ReadJSON(strings.NewReader(testJSON))
+
+	if err != nil {
+		t.Fatalf("ReadJSON() returned an error: %s", err)
+	}
+
+	checkQuizFile(t, q)
+
+}
+
+func TestReadYAMLMissingAnswers(t *testing.T) {
+
+	_, err := ReadYAML(strings.NewReader("problems:\n  - question: 1+1\n"))
+
+	if err == nil {
+		t.Errorf("Expected an error for a problem without answers")
+	}
+
+}
+
+func TestLoadUnsupportedExtension(t *testing.T) {
+
+	_, err := Load("problems.txt")
+
+	if err == nil {
+		t.Errorf("Expected an error for an unsupported file extension")
+	}
+
+}
+
+func TestCheck(t *testing.T) {
+
+	q, _ := ReadYAML(strings.NewReader(testYAML))
+
+	testCases := []struct {
+		problem int
+		given   string
+		want    bool
+	}{
+		{0, "Paris", true},
+		{0, "b", true},
+		{0, "B", true},
+		{0, "a", false},
+		{0, "d", false},
+		{1, "Blue", true},
+		{1, "green", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.given, func(t *testing.T) {
+			if got := q.Problems[tc.problem].Check(tc.given); got != tc.want {
+				t.Errorf("Got %t, want %t", got, tc.want)
+			}
+		})
+	}
+
+}
+
+func TestRunWeighted(t *testing.T) {
+
+	q, _ := ReadYAML(strings.NewReader(testYAML))
+
+	var out strings.Builder
+
+	result, err := NewSession(q).Run(context.Background(), strings.NewReader("b\npurple\n"), &out)
+
+	if err != nil {
+		t.Fatalf("Run() returned an error: %s", err)
+	}
+
+	if result.Score != 2 || result.MaxScore != 3 {
+		t.Errorf("Got a score of %g out of %g, want 2 out of 3", result.Score, result.MaxScore)
+	}
+
+	for _, s := range []string{"  b) Paris", "Paris has been the capital since 987."} {
+		if !strings.Contains(out.String(), s) {
+			t.Errorf("Expected %q in the output, got %q", s, out.String())
+		}
+	}
+
+}
@@ -0,0 +1,105 @@
+package quiz
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"time"
+)
+
+// In-quiz commands that may be entered in place of an answer.
+const (
+	cmdSkip  = ":skip"
+	cmdHint  = ":hint"
+	cmdPause = ":pause"
+	cmdQuit  = ":quit"
+)
+
+// readLines starts a single goroutine that sends each line read from r to
+// the returned channel. The channel is closed once r is exhausted or ctx is
+// cancelled.
+func readLines(ctx context.Context, r io.Reader) <-chan string {
+
+	lines := make(chan string)
+
+	go func() {
+
+		defer close(lines)
+
+		scanner := bufio.NewScanner(r)
+
+		for scanner.Scan() {
+			select {
+			case lines <- scanner.Text():
+			case <-ctx.Done():
+				return
+			}
+		}
+
+	}()
+
+	return lines
+
+}
+
+// clock is a stopwatch with an optional countdown that can be paused and
+// resumed. The zero value never runs out.
+type clock struct {
+	started  time.Time
+	deadline time.Time
+	pausedAt time.Time
+	paused   time.Duration
+}
+
+func newClock(limit time.Duration) *clock {
+
+	c := &clock{started: time.Now()}
+
+	if limit > 0 {
+		c.deadline = c.started.Add(limit)
+	}
+
+	return c
+
+}
+
+func (c *clock) pause() {
+	c.pausedAt = time.Now()
+}
+
+func (c *clock) resume() {
+
+	if c.pausedAt.IsZero() {
+		return
+	}
+
+	d := time.Since(c.pausedAt)
+
+	c.paused += d
+
+	if !c.deadline.IsZero() {
+		c.deadline = c.deadline.Add(d)
+	}
+
+	c.pausedAt = time.Time{}
+
+}
+
+// elapsed reports how long the clock has been running, excluding pauses.
+func (c *clock) elapsed() time.Duration {
+	return time.Since(c.started) - c.paused
+}
+
+// timer returns a channel that fires when the clock runs out, along with a
+// function that releases it. The channel is nil if the clock has no limit.
+func (c *clock) timer() (<-chan time.Time, func()) {
+
+	if c.deadline.IsZero() {
+		return nil, func() {}
+	}
+
+	t := time.NewTimer(time.Until(c.deadline))
+
+	return t.C, func() { t.Stop() }
+
+}
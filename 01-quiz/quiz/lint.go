@@ -0,0 +1,286 @@
+package quiz
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Diagnostic describes a problem found in a quiz file. Line is zero when the
+// problem can't be tied to a single line.
+type Diagnostic struct {
+	Line    int
+	Message string
+}
+
+func (d Diagnostic) String() string {
+
+	if d.Line == 0 {
+		return d.Message
+	}
+
+	return fmt.Sprintf("line %d: %s", d.Line, d.Message)
+
+}
+
+var (
+	bomUTF8    = []byte{0xEF, 0xBB, 0xBF}
+	bomUTF16LE = []byte{0xFF, 0xFE}
+	bomUTF16BE = []byte{0xFE, 0xFF}
+)
+
+// Lint checks the quiz file at path for mistakes, without stopping at the
+// first one. The format of the file is chosen by its extension, as in Load.
+func Lint(path string) ([]Diagnostic, error) {
+
+	data, err := ioutil.ReadFile(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Check the encoding first, as nothing else can be trusted if it's wrong
+
+	if bytes.HasPrefix(data, bomUTF16LE) || bytes.HasPrefix(data, bomUTF16BE) {
+		return []Diagnostic{{1, "file is UTF-16 encoded; save it as UTF-8 instead"}}, nil
+	}
+
+	var diags []Diagnostic
+
+	if bytes.HasPrefix(data, bomUTF8) {
+
+		diags = append(diags, Diagnostic{1, "file begins with a UTF-8 byte order mark"})
+
+		data = data[len(bomUTF8):]
+
+	}
+
+	for i, line := range bytes.Split(data, []byte("\n")) {
+		if !utf8.Valid(line) {
+			diags = append(diags, Diagnostic{i + 1, "line is not valid UTF-8"})
+		}
+	}
+
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".csv":
+		diags = append(diags, LintCSV(bytes.NewReader(data))...)
+	case ".json":
+		diags = append(diags, lintJSON(data)...)
+	case ".yaml", ".yml":
+		diags = append(diags, lintYAML(data)...)
+	default:
+		return nil, fmt.Errorf("unsupported quiz file extension '%s'", ext)
+	}
+
+	return diags, nil
+
+}
+
+// LintCSV checks CSV quiz data for records with the wrong number of columns,
+// empty questions or answers, invalid time limits or difficulties and
+// duplicate questions.
+func LintCSV(r io.Reader) []Diagnostic {
+
+	var diags []Diagnostic
+
+	reader := csv.NewReader(r)
+
+	reader.FieldsPerRecord = -1
+
+	seen := map[string]int{}
+
+	for {
+
+		entry, err := reader.Read()
+
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+
+			line := 0
+
+			if pe, ok := err.(*csv.ParseError); ok {
+				line, err = pe.Line, pe.Err
+			}
+
+			// The reader can't reliably recover from a malformed record
+
+			return append(diags, Diagnostic{line, fmt.Sprintf("malformed record (%s)", err)})
+
+		}
+
+		line, _ := reader.FieldPos(0)
+
+		if len(entry) < 2 || len(entry) > 5 {
+			diags = append(diags, Diagnostic{line, fmt.Sprintf("expected 2 to 5 columns, found %d", len(entry))})
+		}
+
+		question := strings.TrimSpace(entry[0])
+
+		if question == "" {
+			diags = append(diags, Diagnostic{line, "empty question"})
+		}
+
+		if len(entry) > 1 && strings.TrimSpace(entry[1]) == "" {
+			diags = append(diags, Diagnostic{line, "empty answer"})
+		}
+
+		if len(entry) > 2 && strings.TrimSpace(entry[2]) != "" {
+			if _, err := parseSeconds(entry[2]); err != nil {
+				diags = append(diags, Diagnostic{line, err.Error()})
+			}
+		}
+
+		if len(entry) > 4 && strings.TrimSpace(entry[4]) != "" {
+			if _, err := parseDifficulty(entry[4]); err != nil {
+				diags = append(diags, Diagnostic{line, err.Error()})
+			}
+		}
+
+		if question == "" {
+			continue
+		}
+
+		if first, ok := seen[normalize(question)]; ok {
+			diags = append(diags, Diagnostic{line, fmt.Sprintf("duplicate question '%s' (first seen on line %d)", question, first)})
+		} else {
+			seen[normalize(question)] = line
+		}
+
+	}
+
+	return diags
+
+}
+
+func lintJSON(data []byte) []Diagnostic {
+
+	var f quizFile
+
+	d := json.NewDecoder(bytes.NewReader(data))
+
+	d.DisallowUnknownFields()
+
+	if err := d.Decode(&f); err != nil {
+
+		line := 0
+
+		if se, ok := err.(*json.SyntaxError); ok {
+			line = bytes.Count(data[:se.Offset], []byte("\n")) + 1
+		}
+
+		return []Diagnostic{{line, fmt.Sprintf("malformed JSON (%s)", err)}}
+
+	}
+
+	return f.lint()
+
+}
+
+func lintYAML(data []byte) []Diagnostic {
+
+	var f quizFile
+
+	if err := yaml.UnmarshalStrict(data, &f); err != nil {
+		return []Diagnostic{{0, fmt.Sprintf("malformed YAML (%s)", err)}}
+	}
+
+	return f.lint()
+
+}
+
+// lint checks each of the problems in a decoded quiz file. Decoding loses
+// track of line numbers, so problems are referred to by their position.
+func (f quizFile) lint() []Diagnostic {
+
+	var diags []Diagnostic
+
+	report := func(i int, format string, args ...interface{}) {
+		diags = append(diags, Diagnostic{0, fmt.Sprintf("problem %d: ", i+1) + fmt.Sprintf(format, args...)})
+	}
+
+	if f.Match != "" {
+		if _, err := ParseMatcher(f.Match); err != nil {
+			diags = append(diags, Diagnostic{0, err.Error()})
+		}
+	}
+
+	if len(f.Problems) == 0 {
+		diags = append(diags, Diagnostic{0, "quiz has no problems"})
+	}
+
+	seen := map[string]int{}
+
+	for i, e := range f.Problems {
+
+		question := strings.TrimSpace(e.Question)
+
+		if question == "" {
+			report(i, "empty question")
+		} else if first, ok := seen[normalize(question)]; ok {
+			report(i, "duplicate question '%s' (first seen in problem %d)", question, first+1)
+		} else {
+			seen[normalize(question)] = i
+		}
+
+		if len(e.Answers) == 0 {
+			report(i, "no answers")
+		}
+
+		for _, a := range e.Answers {
+			if strings.TrimSpace(a) == "" {
+				report(i, "empty answer")
+			}
+		}
+
+		if len(e.Choices) > 0 && !e.answerIsAChoice() {
+			report(i, "none of the answers is among the choices")
+		}
+
+		if e.Points < 0 {
+			report(i, "negative points")
+		}
+
+		if e.TimeLimit < 0 {
+			report(i, "negative time limit")
+		}
+
+		if e.Difficulty < 0 {
+			report(i, "negative difficulty")
+		}
+
+		if e.Match != "" {
+			if _, err := ParseMatcher(e.Match); err != nil {
+				report(i, "%s", err)
+			}
+		}
+
+	}
+
+	return diags
+
+}
+
+func (e problemEntry) answerIsAChoice() bool {
+
+	for _, a := range e.Answers {
+		for _, c := range e.Choices {
+			if normalize(a) == normalize(c) {
+				return true
+			}
+		}
+	}
+
+	return false
+
+}
@@ -0,0 +1,128 @@
+package quiz
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLintCSV(t *testing.T) {
+
+	data := strings.Join([]string{
+		"5+5,10",
+		"1+1",
+		",2",
+		"8+3,",
+		"5+5,10",
+		"1+2,3,soon",
+		"8+6,14,10,sums,easy",
+		"3+1,4,10,sums,1,extra",
+	}, "\n")
+
+	got := LintCSV(strings.NewReader(data))
+
+	want := []Diagnostic{
+		{2, "expected 2 to 5 columns, found 1"},
+		{3, "empty question"},
+		{4, "empty answer"},
+		{5, "duplicate question '5+5' (first seen on line 1)"},
+		{6, "invalid time limit 'soon'"},
+		{7, "invalid difficulty 'easy'"},
+		{8, "expected 2 to 5 columns, found 6"},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Got %v, want %v", got, want)
+	}
+
+}
+
+func TestLintCSVMalformed(t *testing.T) {
+
+	got := LintCSV(strings.NewReader("5+5,10\n\"1+1,2\n"))
+
+	if len(got) != 1 || !strings.HasPrefix(got[0].Message, "malformed record") {
+		t.Errorf("Got %v, want a single malformed record", got)
+	}
+
+}
+
+func writeFile(t *testing.T, name string, data string) string {
+
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+
+}
+
+func TestLintEncoding(t *testing.T) {
+
+	got, err := Lint(writeFile(t, "bom.csv", "\xEF\xBB\xBF5+5,10\n1+1,\xFF\n"))
+
+	if err != nil {
+		t.Fatalf("Lint() returned an error: %s", err)
+	}
+
+	want := []Diagnostic{
+		{1, "file begins with a UTF-8 byte order mark"},
+		{2, "line is not valid UTF-8"},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Got %v, want %v", got, want)
+	}
+
+	got, _ = Lint(writeFile(t, "utf16.csv", "\xFF\xFE5\x00+\x005\x00"))
+
+	if len(got) != 1 || !strings.Contains(got[0].Message, "UTF-16") {
+		t.Errorf("Got %v, want a single UTF-16 diagnostic", got)
+	}
+
+}
+
+func TestLintYAML(t *testing.T) {
+
+	got, err := Lint(writeFile(t, "quiz.yaml", `
+match: soundex
+problems:
+  - question: Capital of France?
+    answers: [Paris]
+    choices: [Berlin, Madrid]
+  - question: capital of france?
+    answers: [""]
+`))
+
+	if err != nil {
+		t.Fatalf("Lint() returned an error: %s", err)
+	}
+
+	want := []Diagnostic{
+		{0, "unknown matcher 'soundex'"},
+		{0, "problem 1: none of the answers is among the choices"},
+		{0, "problem 2: duplicate question 'capital of france?' (first seen in problem 1)"},
+		{0, "problem 2: empty answer"},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Got %v, want %v", got, want)
+	}
+
+}
+
+func TestLintJSONSyntax(t *testing.T) {
+
+	got, _ := Lint(writeFile(t, "quiz.json", "{\n  \"problems\": [\n    {\"question\": }\n  ]\n}"))
+
+	if len(got) != 1 || got[0].Line != 3 {
+		t.Errorf("Got %v, want a single diagnostic on line 3", got)
+	}
+
+}
@@ -0,0 +1,230 @@
+package quiz
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Matcher reports whether a given answer should be accepted in place of the
+// expected answer.
+type Matcher func(given, want string) bool
+
+// Exact accepts answers that are identical to the expected answer, ignoring
+// only surrounding whitespace.
+func Exact() Matcher {
+
+	return func(given, want string) bool {
+		return strings.TrimSpace(given) == strings.TrimSpace(want)
+	}
+
+}
+
+// CaseInsensitive accepts answers that differ from the expected answer only
+// in case. This is the default Matcher.
+func CaseInsensitive() Matcher {
+
+	return func(given, want string) bool {
+		return normalize(given) == normalize(want)
+	}
+
+}
+
+// Numeric accepts answers that are within tolerance of the expected answer
+// when both are read as numbers, so that "10.0" matches "10" and "1,000"
+// matches "1000". Answers that aren't numbers are compared case-insensitively.
+func Numeric(tolerance float64) Matcher {
+
+	fallback := CaseInsensitive()
+
+	return func(given, want string) bool {
+
+		g, err := parseNumber(given)
+
+		if err != nil {
+			return fallback(given, want)
+		}
+
+		w, err := parseNumber(want)
+
+		if err != nil {
+			return fallback(given, want)
+		}
+
+		return math.Abs(g-w) <= tolerance
+
+	}
+
+}
+
+// Levenshtein accepts answers that are no more than max single-character
+// edits away from the expected answer, ignoring case. Numeric answers, and
+// answers no longer than max, must match exactly, since any edit to them
+// yields a different answer.
+func Levenshtein(max int) Matcher {
+
+	return func(given, want string) bool {
+
+		given, want = normalize(given), normalize(want)
+
+		if _, err := parseNumber(want); err == nil || utf8.RuneCountInString(want) <= max {
+			return given == want
+		}
+
+		return levenshtein(given, want) <= max
+
+	}
+
+}
+
+// Folded accepts answers that match the expected answer once both have been
+// Unicode-normalized, with accents and case folded away, so that "Tokyo"
+// matches "Tōkyō".
+func Folded() Matcher {
+
+	return func(given, want string) bool {
+		return fold(given) == fold(want)
+	}
+
+}
+
+// ParseMatcher returns the Matcher described by spec, which names a matcher
+// and, for those that take one, a parameter:
+//
+//	exact
+//	case-insensitive
+//	numeric[:tolerance]
+//	levenshtein[:max-edits]
+//	folded
+func ParseMatcher(spec string) (Matcher, error) {
+
+	name, param := spec, ""
+
+	if i := strings.Index(spec, ":"); i >= 0 {
+		name, param = spec[:i], spec[i+1:]
+	}
+
+	switch strings.ToLower(strings.TrimSpace(name)) {
+
+	case "exact":
+		return Exact(), nil
+
+	case "", "case-insensitive":
+		return CaseInsensitive(), nil
+
+	case "numeric":
+
+		if param == "" {
+			return Numeric(0), nil
+		}
+
+		tolerance, err := strconv.ParseFloat(param, 64)
+
+		if err != nil || tolerance < 0 {
+			return nil, fmt.Errorf("invalid numeric tolerance '%s'", param)
+		}
+
+		return Numeric(tolerance), nil
+
+	case "levenshtein":
+
+		if param == "" {
+			return Levenshtein(1), nil
+		}
+
+		max, err := strconv.Atoi(param)
+
+		if err != nil || max < 0 {
+			return nil, fmt.Errorf("invalid levenshtein distance '%s'", param)
+		}
+
+		return Levenshtein(max), nil
+
+	case "folded":
+		return Folded(), nil
+
+	default:
+		return nil, fmt.Errorf("unknown matcher '%s'", name)
+
+	}
+
+}
+
+// parseNumber reads a number, ignoring any thousands separators.
+func parseNumber(s string) (float64, error) {
+
+	s = strings.TrimSpace(s)
+
+	s = strings.NewReplacer(",", "", "_", "", " ", "").Replace(s)
+
+	return strconv.ParseFloat(s, 64)
+
+}
+
+// levenshtein computes the edit distance between two strings.
+func levenshtein(a, b string) int {
+
+	ra, rb := []rune(a), []rune(b)
+
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+
+		curr[0] = i
+
+		for j := 1; j <= len(rb); j++ {
+
+			cost := 1
+
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+
+			curr[j] = minInt(prev[j]+1, minInt(curr[j-1]+1, prev[j-1]+cost))
+
+		}
+
+		prev, curr = curr, prev
+
+	}
+
+	return prev[len(rb)]
+
+}
+
+func minInt(a, b int) int {
+
+	if a < b {
+		return a
+	}
+
+	return b
+
+}
+
+// fold strips accents and case from a string.
+func fold(s string) string {
+
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+
+	folded, _, err := transform.String(t, s)
+
+	if err != nil {
+		folded = s
+	}
+
+	return normalize(folded)
+
+}
@@ -0,0 +1,91 @@
+package quiz
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMatchers(t *testing.T) {
+
+	testCases := []struct {
+		spec  string
+		given string
+		want  string
+		match bool
+	}{
+		{"exact", "Paris", "Paris", true},
+		{"exact", "paris", "Paris", false},
+		{"case-insensitive", " PARIS ", "Paris", true},
+		{"numeric", "10.0", "10", true},
+		{"numeric", "1,000", "1000", true},
+		{"numeric", "10.1", "10", false},
+		{"numeric:0.2", "10.1", "10", true},
+		{"numeric", "ten", "Ten", true},
+		{"levenshtein:1", "Pariss", "Paris", true},
+		{"levenshtein:1", "Parsi", "Paris", false},
+		{"levenshtein:2", "Parsi", "Paris", true},
+		{"levenshtein:1", "7", "4", false},
+		{"levenshtein:1", "1999", "1998", false},
+		{"levenshtein:1", "a", "b", false},
+		{"levenshtein:2", "Ox", "Ax", false},
+		{"levenshtein:1", "A", "a", true},
+		{"folded", "Tokyo", "Tōkyō", true},
+		{"folded", "SAO PAULO", "São Paulo", true},
+		{"folded", "Kyoto", "Tōkyō", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.spec+"/"+tc.given, func(t *testing.T) {
+
+			match, err := ParseMatcher(tc.spec)
+
+			if err != nil {
+				t.Fatalf("ParseMatcher() returned an error: %s", err)
+			}
+
+			if got := match(tc.given, tc.want); got != tc.match {
+				t.Errorf("Got %t, want %t", got, tc.match)
+			}
+
+		})
+	}
+
+}
+
+func TestParseMatcherInvalid(t *testing.T) {
+
+	for _, spec := range []string{"soundex", "numeric:abc", "levenshtein:-1"} {
+		if _, err := ParseMatcher(spec); err == nil {
+			t.Errorf("Expected an error for '%s'", spec)
+		}
+	}
+
+}
+
+func TestMatcherPrecedence(t *testing.T) {
+
+	q, err := ReadYAML(strings.NewReader(`
+match: numeric
+problems:
+  - question: 5+5
+    answers: ["10"]
+  - question: Capital of Japan
+    answers: [Tōkyō]
+    match: folded
+`))
+
+	if err != nil {
+		t.Fatalf("ReadYAML() returned an error: %s", err)
+	}
+
+	s := NewSession(q)
+
+	if !s.quiz.Problems[0].Check("10.0") {
+		t.Errorf("Expected the quiz matcher to accept '10.0'")
+	}
+
+	if !s.quiz.Problems[1].Check("tokyo") {
+		t.Errorf("Expected the problem matcher to accept 'tokyo'")
+	}
+
+}
@@ -0,0 +1,233 @@
+package quiz
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Problem is a single question along with every answer that is accepted for
+// it. Choices, if present, turn the problem into a multiple choice question
+// that may be answered by letter. Points weighs the problem when scoring,
+// and Explanation is shown once the problem has been dealt with. TimeLimit,
+// if set, bounds the time allowed to answer it. Match, if set, overrides
+// the way answers are compared for this problem. Category and Tags group
+// related problems, and Difficulty rates the problem from 1 (the easiest)
+// upwards, for use by adaptive quizzes.
+type Problem struct {
+	Question    string
+	Answers     []string
+	Choices     []string
+	Points      float64
+	Explanation string
+	TimeLimit   time.Duration
+	Match       Matcher
+	Category    string
+	Tags        []string
+	Difficulty  int
+}
+
+// Check reports whether the given answer is one of the accepted answers. For
+// multiple choice problems, the letter of a choice stands in for its text.
+func (p Problem) Check(given string) bool {
+
+	match := p.Match
+
+	if match == nil {
+		match = CaseInsensitive()
+	}
+
+	candidates := []string{given}
+
+	if choice, ok := p.choice(given); ok {
+		candidates = append(candidates, choice)
+	}
+
+	for _, c := range candidates {
+		for _, a := range p.Answers {
+			if match(c, a) {
+				return true
+			}
+		}
+	}
+
+	return false
+
+}
+
+// Expected returns the first of the accepted answers, for display purposes.
+func (p Problem) Expected() string {
+
+	if len(p.Answers) == 0 {
+		return ""
+	}
+
+	return p.Answers[0]
+
+}
+
+// Weight returns the number of points that the problem is worth.
+func (p Problem) Weight() float64 {
+
+	if p.Points <= 0 {
+		return 1
+	}
+
+	return p.Points
+
+}
+
+// Level returns the difficulty of the problem, treating unrated problems as
+// the easiest.
+func (p Problem) Level() int {
+
+	if p.Difficulty < 1 {
+		return 1
+	}
+
+	return p.Difficulty
+
+}
+
+// HasTag reports whether the problem's category or any of its tags matches
+// the given tag, ignoring case.
+func (p Problem) HasTag(tag string) bool {
+
+	tag = normalize(tag)
+
+	if normalize(p.Category) == tag {
+		return true
+	}
+
+	for _, t := range p.Tags {
+		if normalize(t) == tag {
+			return true
+		}
+	}
+
+	return false
+
+}
+
+// choice looks up the text of the choice labelled by the given letter.
+func (p Problem) choice(letter string) (string, bool) {
+
+	letter = normalize(letter)
+
+	if len(letter) != 1 || letter[0] < 'a' {
+		return "", false
+	}
+
+	i := int(letter[0] - 'a')
+
+	if i >= len(p.Choices) {
+		return "", false
+	}
+
+	return p.Choices[i], true
+
+}
+
+// ReadCSV decodes a list of problems from CSV data in which each record
+// holds a question followed by its answer. Optionally, the record may go on
+// to hold a time limit for the question in seconds, a category and a
+// difficulty, in that order. A leading UTF-8 byte order mark is ignored.
+func ReadCSV(r io.Reader) ([]Problem, error) {
+
+	br := bufio.NewReader(r)
+
+	if bom, err := br.Peek(len(bomUTF8)); err == nil && bytes.Equal(bom, bomUTF8) {
+		br.Discard(len(bomUTF8))
+	}
+
+	reader := csv.NewReader(br)
+
+	reader.FieldsPerRecord = -1
+
+	entries, err := reader.ReadAll()
+
+	if err != nil {
+		return nil, err
+	}
+
+	problems := make([]Problem, len(entries))
+
+	for i, entry := range entries {
+
+		if len(entry) < 2 {
+			return nil, fmt.Errorf("record %d: expected a question and an answer", i+1)
+		}
+
+		problems[i] = Problem{
+			Question: entry[0],
+			Answers:  []string{entry[1]},
+		}
+
+		if len(entry) > 2 && strings.TrimSpace(entry[2]) != "" {
+
+			limit, err := parseSeconds(entry[2])
+
+			if err != nil {
+				return nil, fmt.Errorf("record %d: %s", i+1, err)
+			}
+
+			problems[i].TimeLimit = limit
+
+		}
+
+		if len(entry) > 3 {
+			problems[i].Category = strings.TrimSpace(entry[3])
+		}
+
+		if len(entry) > 4 && strings.TrimSpace(entry[4]) != "" {
+
+			difficulty, err := parseDifficulty(entry[4])
+
+			if err != nil {
+				return nil, fmt.Errorf("record %d: %s", i+1, err)
+			}
+
+			problems[i].Difficulty = difficulty
+
+		}
+
+	}
+
+	return problems, nil
+
+}
+
+// parseSeconds reads a (possibly fractional) number of seconds.
+func parseSeconds(s string) (time.Duration, error) {
+
+	seconds, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+
+	if err != nil || seconds < 0 {
+		return 0, fmt.Errorf("invalid time limit '%s'", s)
+	}
+
+	return time.Duration(seconds * float64(time.Second)), nil
+
+}
+
+// parseDifficulty reads a difficulty rating of 1 or more.
+func parseDifficulty(s string) (int, error) {
+
+	difficulty, err := strconv.Atoi(strings.TrimSpace(s))
+
+	if err != nil || difficulty < 1 {
+		return 0, fmt.Errorf("invalid difficulty '%s'", s)
+	}
+
+	return difficulty, nil
+
+}
+
+func normalize(input string) string {
+	return strings.TrimSpace(strings.ToLower(input))
+}
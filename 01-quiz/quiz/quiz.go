@@ -0,0 +1,556 @@
+package quiz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Quiz is a list of problems to be answered within an optional time limit.
+// QuestionLimit, if set, bounds the time spent on any problem that does not
+// specify its own limit. Likewise, Match decides how answers are compared
+// for any problem without its own Matcher.
+//
+// Problems are asked in order, unless the quiz is Adaptive: then the next
+// problem is the one whose difficulty best suits the learner's accuracy so
+// far. Ask, if set, ends a session once that many problems have been asked.
+type Quiz struct {
+	Problems      []Problem
+	TimeLimit     time.Duration
+	QuestionLimit time.Duration
+	Match         Matcher
+	Adaptive      bool
+	Ask           int
+}
+
+// Response records how a single problem was dealt with during a session.
+type Response struct {
+	Problem Problem
+	Answer  string
+	Correct bool
+	Skipped bool
+	Expired bool
+	Elapsed time.Duration
+}
+
+// Result summarizes a quiz session once it has ended.
+type Result struct {
+	Total     int
+	Score     float64
+	MaxScore  float64
+	Answered  int
+	Correct   int
+	Skipped   int
+	Expired   int
+	Hints     int
+	Pauses    int
+	TimedOut  bool
+	Quit      bool
+	Responses []Response
+}
+
+func (r Result) String() string {
+
+	s := fmt.Sprintf("You scored %d out of %d.", r.Correct, r.Total)
+
+	if r.MaxScore != float64(r.Total) {
+		s += fmt.Sprintf(" (%g out of %g points)", r.Score, r.MaxScore)
+	}
+
+	if r.Skipped > 0 || r.Hints > 0 || r.Pauses > 0 {
+		s += fmt.Sprintf("\nSkipped: %d, hints: %d, pauses: %d.", r.Skipped, r.Hints, r.Pauses)
+	}
+
+	if r.Expired > 0 {
+		s += fmt.Sprintf("\nRan out of time on %d question(s).", r.Expired)
+	}
+
+	return s
+
+}
+
+// Slowest returns up to n responses, ordered from the longest to the shortest
+// time spent on them.
+func (r Result) Slowest(n int) []Response {
+
+	responses := make([]Response, len(r.Responses))
+
+	copy(responses, r.Responses)
+
+	sort.SliceStable(responses, func(i, j int) bool {
+		return responses[i].Elapsed > responses[j].Elapsed
+	})
+
+	if n < len(responses) {
+		responses = responses[:n]
+	}
+
+	return responses
+
+}
+
+// Accuracy is the share of correct answers within a category of problems.
+type Accuracy struct {
+	Category string
+	Asked    int
+	Correct  int
+}
+
+func (a Accuracy) String() string {
+	return fmt.Sprintf("%s: %d out of %d (%.0f%%)", a.Category, a.Correct, a.Asked, 100*float64(a.Correct)/float64(a.Asked))
+}
+
+// ByCategory breaks down the accuracy of the responses by the category of
+// their problems, in alphabetical order. Uncategorized problems are grouped
+// together under "uncategorized".
+func (r Result) ByCategory() []Accuracy {
+
+	categories := map[string]*Accuracy{}
+
+	for _, resp := range r.Responses {
+
+		category := resp.Problem.Category
+
+		if category == "" {
+			category = "uncategorized"
+		}
+
+		a, ok := categories[category]
+
+		if !ok {
+			a = &Accuracy{Category: category}
+			categories[category] = a
+		}
+
+		a.Asked++
+
+		if resp.Correct {
+			a.Correct++
+		}
+
+	}
+
+	accuracies := make([]Accuracy, 0, len(categories))
+
+	for _, a := range categories {
+		accuracies = append(accuracies, *a)
+	}
+
+	sort.Slice(accuracies, func(i, j int) bool {
+		return accuracies[i].Category < accuracies[j].Category
+	})
+
+	return accuracies
+
+}
+
+// Session tracks a single attempt at a Quiz, one problem at a time. Run
+// drives a session from a terminal, while other front ends may step through
+// it directly with Current, Answer, Skip and Expire.
+type Session struct {
+	quiz    Quiz
+	result  Result
+	pending []int
+	current int
+	ended   bool
+}
+
+// NewSession prepares a new attempt at the given Quiz.
+func NewSession(q Quiz) *Session {
+
+	problems := make([]Problem, len(q.Problems))
+
+	pending := make([]int, len(q.Problems))
+
+	for i, p := range q.Problems {
+
+		if p.Match == nil {
+			p.Match = q.Match
+		}
+
+		problems[i] = p
+
+		pending[i] = i
+
+	}
+
+	q.Problems = problems
+
+	s := &Session{
+		quiz:    q,
+		pending: pending,
+		current: -1,
+		result: Result{
+			Total: len(q.Problems),
+		},
+	}
+
+	if q.Ask > 0 && q.Ask < s.result.Total {
+		s.result.Total = q.Ask
+	}
+
+	// When only some of the problems will be asked, the points available
+	// are only known as each problem is picked
+
+	if s.result.Total == len(q.Problems) {
+		for _, p := range q.Problems {
+			s.result.MaxScore += p.Weight()
+		}
+	}
+
+	return s
+
+}
+
+// Current returns the problem that is waiting to be dealt with, along with
+// the number of problems that came before it. It returns false once the
+// session has ended.
+func (s *Session) Current() (Problem, int, bool) {
+
+	asked := len(s.result.Responses)
+
+	if s.ended || asked >= s.result.Total {
+		return Problem{}, asked, false
+	}
+
+	if s.current < 0 {
+		s.pick()
+	}
+
+	return s.quiz.Problems[s.current], asked, true
+
+}
+
+// pick chooses the next problem to be asked from those still pending.
+func (s *Session) pick() {
+
+	next := 0
+
+	if s.quiz.Adaptive {
+		next = s.adapt()
+	}
+
+	s.current = s.pending[next]
+
+	s.pending = append(s.pending[:next], s.pending[next+1:]...)
+
+	if s.result.Total < len(s.quiz.Problems) {
+		s.result.MaxScore += s.quiz.Problems[s.current].Weight()
+	}
+
+}
+
+// Limit returns the time allowed for a single problem, if any.
+func (s *Session) Limit(p Problem) time.Duration {
+
+	if p.TimeLimit > 0 {
+		return p.TimeLimit
+	}
+
+	return s.quiz.QuestionLimit
+
+}
+
+// Answer checks an answer to the current problem and moves on to the next.
+func (s *Session) Answer(given string, elapsed time.Duration) Response {
+
+	return s.resolve(Response{Answer: given, Elapsed: elapsed}, func(r *Response) {
+
+		s.result.Answered++
+
+		if r.Problem.Check(given) {
+			s.result.Correct++
+			s.result.Score += r.Problem.Weight()
+			r.Correct = true
+		}
+
+	})
+
+}
+
+// Skip leaves the current problem unanswered and moves on to the next.
+func (s *Session) Skip(elapsed time.Duration) Response {
+
+	return s.resolve(Response{Skipped: true, Elapsed: elapsed}, func(*Response) {
+		s.result.Skipped++
+	})
+
+}
+
+// Expire records that time ran out on the current problem, and moves on to
+// the next.
+func (s *Session) Expire(elapsed time.Duration) Response {
+
+	return s.resolve(Response{Expired: true, Elapsed: elapsed}, func(*Response) {
+		s.result.Expired++
+	})
+
+}
+
+func (s *Session) resolve(r Response, update func(r *Response)) Response {
+
+	p, _, ok := s.Current()
+
+	if !ok {
+		return Response{}
+	}
+
+	r.Problem = p
+
+	update(&r)
+
+	s.result.Responses = append(s.result.Responses, r)
+
+	s.current = -1
+
+	return r
+
+}
+
+// Hint reveals the first character of the answer to the current problem.
+func (s *Session) Hint() string {
+
+	p, _, ok := s.Current()
+
+	if !ok {
+		return ""
+	}
+
+	s.result.Hints++
+
+	return hint(p.Expected())
+
+}
+
+// Pause records that the clock was stopped.
+func (s *Session) Pause() {
+	s.result.Pauses++
+}
+
+// Quit ends the session early, at the learner's request.
+func (s *Session) Quit() {
+	s.ended = true
+	s.result.Quit = true
+}
+
+// TimeOut ends the session because its time limit was reached.
+func (s *Session) TimeOut() {
+	s.ended = true
+	s.result.TimedOut = true
+}
+
+// Result returns a summary of the session so far.
+func (s *Session) Result() Result {
+
+	r := s.result
+
+	r.Responses = make([]Response, len(s.result.Responses))
+
+	copy(r.Responses, s.result.Responses)
+
+	return r
+
+}
+
+var (
+	errTimeout = errors.New("time limit reached")
+	errExpired = errors.New("question time limit reached")
+	errEOF     = errors.New("input exhausted")
+)
+
+// Run presents each problem to out and reads answers from in, one line at a
+// time, until every problem has been answered, the input is exhausted, the
+// time limit is reached or the learner quits. A problem that runs past its
+// own time limit is left unanswered. Besides an answer, a line may hold one
+// of the following commands:
+//
+//	:skip   move on to the next problem
+//	:hint   reveal the first character of the answer
+//	:pause  stop the clock until the next line is entered
+//	:quit   end the quiz early
+//
+// Run only returns an error if ctx is cancelled by the caller.
+func (s *Session) Run(ctx context.Context, in io.Reader, out io.Writer) (Result, error) {
+
+	ctx, cancel := context.WithCancel(ctx)
+
+	defer cancel()
+
+	lines := readLines(ctx, in)
+
+	quizClock := newClock(s.quiz.TimeLimit)
+
+	for {
+
+		p, i, ok := s.Current()
+
+		if !ok {
+			return s.Result(), nil
+		}
+
+		present(out, i, p)
+
+		questionClock := newClock(s.Limit(p))
+
+	question:
+		for {
+
+			line, err := s.read(ctx, lines, quizClock, questionClock)
+
+			if err == errExpired {
+
+				fmt.Fprintln(out, "Out of time!")
+
+				s.Expire(questionClock.elapsed())
+
+				explain(out, p)
+
+				break question
+
+			}
+
+			if err != nil {
+				return s.finish(err)
+			}
+
+			switch strings.TrimSpace(line) {
+
+			case cmdSkip:
+
+				s.Skip(questionClock.elapsed())
+
+				explain(out, p)
+
+				break question
+
+			case cmdHint:
+
+				fmt.Fprintf(out, "Hint: the answer starts with %q.\n", s.Hint())
+
+			case cmdPause:
+
+				s.Pause()
+
+				quizClock.pause()
+
+				questionClock.pause()
+
+				fmt.Fprintln(out, "Paused. Press Enter to resume.")
+
+				if _, err := s.read(ctx, lines, &clock{}, &clock{}); err != nil {
+					return s.finish(err)
+				}
+
+				quizClock.resume()
+
+				questionClock.resume()
+
+				present(out, i, p)
+
+			case cmdQuit:
+
+				s.Quit()
+
+				return s.Result(), nil
+
+			default:
+
+				s.Answer(line, questionClock.elapsed())
+
+				explain(out, p)
+
+				break question
+
+			}
+
+		}
+
+	}
+
+}
+
+// present writes a problem, along with any choices, to out.
+func present(out io.Writer, i int, p Problem) {
+
+	fmt.Fprintf(out, "Problem #%d: %s = \n", i+1, p.Question)
+
+	for j, c := range p.Choices {
+		fmt.Fprintf(out, "  %c) %s\n", 'a'+j, c)
+	}
+
+}
+
+// explain writes the explanation for a problem to out, if it has one.
+func explain(out io.Writer, p Problem) {
+
+	if p.Explanation != "" {
+		fmt.Fprintln(out, p.Explanation)
+	}
+
+}
+
+// read waits for the next line of input, unless either clock runs out first.
+func (s *Session) read(ctx context.Context, lines <-chan string, quizClock, questionClock *clock) (string, error) {
+
+	timeout, stopQuiz := quizClock.timer()
+
+	defer stopQuiz()
+
+	expired, stopQuestion := questionClock.timer()
+
+	defer stopQuestion()
+
+	select {
+
+	case <-ctx.Done():
+		return "", ctx.Err()
+
+	case <-timeout:
+		return "", errTimeout
+
+	case <-expired:
+		return "", errExpired
+
+	case line, ok := <-lines:
+
+		if !ok {
+			return "", errEOF
+		}
+
+		return line, nil
+
+	}
+
+}
+
+// finish translates the reason that a session was cut short into its result.
+func (s *Session) finish(err error) (Result, error) {
+
+	switch err {
+	case errTimeout:
+		s.TimeOut()
+	case errEOF:
+	default:
+		return s.Result(), err
+	}
+
+	return s.Result(), nil
+
+}
+
+func hint(answer string) string {
+
+	r, _ := utf8.DecodeRuneInString(strings.TrimSpace(answer))
+
+	if r == utf8.RuneError {
+		return ""
+	}
+
+	return string(r)
+
+}
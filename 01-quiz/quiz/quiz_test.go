@@ -0,0 +1,257 @@
+package quiz
+
+import (
+	"context"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testProblems = []Problem{
+	{Question: "5+5", Answers: []string{"10"}},
+	{Question: "1+1", Answers: []string{"2"}},
+	{Question: "8+3", Answers: []string{"11"}},
+}
+
+// checkResult compares the counters of a Result, ignoring its responses.
+func checkResult(t *testing.T, got, want Result) {
+
+	t.Helper()
+
+	got.Responses = nil
+
+	// Print the raw fields rather than the summary given by Result.String()
+	type fields Result
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Got %+v, want %+v", fields(got), fields(want))
+	}
+
+}
+
+func TestReadCSV(t *testing.T) {
+
+	problems, err := ReadCSV(strings.NewReader("5+5,10\n1+1,2\n8+3,11\n"))
+
+	if err != nil {
+		t.Fatalf("ReadCSV() returned an error: %s", err)
+	}
+
+	if len(problems) != len(testProblems) {
+		t.Fatalf("Got %d problems, want %d", len(problems), len(testProblems))
+	}
+
+	for i, p := range problems {
+		if !reflect.DeepEqual(p, testProblems[i]) {
+			t.Errorf("Got %v, want %v", p, testProblems[i])
+		}
+	}
+
+}
+
+func TestReadCSVShortRecord(t *testing.T) {
+
+	_, err := ReadCSV(strings.NewReader("5+5\n1+1\n"))
+
+	if err == nil {
+		t.Errorf("Expected an error for records without an answer")
+	}
+
+}
+
+func TestReadCSVByteOrderMark(t *testing.T) {
+
+	q, err := Load(writeFile(t, "bom.csv", "\xEF\xBB\xBF5+5,10\n"))
+
+	if err != nil || len(q.Problems) != 1 || q.Problems[0].Question != "5+5" {
+		t.Errorf("Got %+v, %v, want the question without the byte order mark", q.Problems, err)
+	}
+
+}
+
+func TestRun(t *testing.T) {
+
+	q := Quiz{Problems: testProblems}
+
+	result, err := NewSession(q).Run(context.Background(), strings.NewReader("10\n 3\nEleven\n"), io.Discard)
+
+	if err != nil {
+		t.Fatalf("Run() returned an error: %s", err)
+	}
+
+	want := Result{Total: 3, Score: 1, MaxScore: 3, Answered: 3, Correct: 1}
+
+	checkResult(t, result, want)
+
+}
+
+func TestRunTimeLimit(t *testing.T) {
+
+	q := Quiz{Problems: testProblems, TimeLimit: 10 * time.Millisecond}
+
+	in, _ := io.Pipe()
+
+	result, err := NewSession(q).Run(context.Background(), in, io.Discard)
+
+	if err != nil {
+		t.Fatalf("Run() returned an error: %s", err)
+	}
+
+	if !result.TimedOut || result.Answered != 0 {
+		t.Errorf("Got %+v, want a timed out result with no answers", result)
+	}
+
+}
+
+func TestRunMultiWordAnswer(t *testing.T) {
+
+	q := Quiz{Problems: []Problem{{Question: "Capital of the UK", Answers: []string{"London Town"}}}}
+
+	result, err := NewSession(q).Run(context.Background(), strings.NewReader("london town\n"), io.Discard)
+
+	if err != nil {
+		t.Fatalf("Run() returned an error: %s", err)
+	}
+
+	if result.Correct != 1 {
+		t.Errorf("Got %d correct, want 1", result.Correct)
+	}
+
+}
+
+func TestRunCommands(t *testing.T) {
+
+	q := Quiz{Problems: testProblems, TimeLimit: time.Second}
+
+	in := strings.NewReader(":hint\n10\n:skip\n:pause\n\n11\n")
+
+	var out strings.Builder
+
+	result, err := NewSession(q).Run(context.Background(), in, &out)
+
+	if err != nil {
+		t.Fatalf("Run() returned an error: %s", err)
+	}
+
+	want := Result{Total: 3, Score: 2, MaxScore: 3, Answered: 2, Correct: 2, Skipped: 1, Hints: 1, Pauses: 1}
+
+	checkResult(t, result, want)
+
+	if !strings.Contains(out.String(), `Hint: the answer starts with "1".`) {
+		t.Errorf("Expected a hint in the output, got %q", out.String())
+	}
+
+}
+
+func TestRunQuit(t *testing.T) {
+
+	q := Quiz{Problems: testProblems}
+
+	result, err := NewSession(q).Run(context.Background(), strings.NewReader("10\n:quit\n2\n"), io.Discard)
+
+	if err != nil {
+		t.Fatalf("Run() returned an error: %s", err)
+	}
+
+	if !result.Quit || result.Answered != 1 {
+		t.Errorf("Got %+v, want a quit result with one answer", result)
+	}
+
+}
+
+func TestReadCSVTimeLimit(t *testing.T) {
+
+	problems, err := ReadCSV(strings.NewReader("5+5,10,2.5\n1+1,2\n"))
+
+	if err != nil {
+		t.Fatalf("ReadCSV() returned an error: %s", err)
+	}
+
+	if problems[0].TimeLimit != 2500*time.Millisecond || problems[1].TimeLimit != 0 {
+		t.Errorf("Got time limits %s and %s, want 2.5s and 0s", problems[0].TimeLimit, problems[1].TimeLimit)
+	}
+
+	if _, err := ReadCSV(strings.NewReader("5+5,10,soon\n")); err == nil {
+		t.Errorf("Expected an error for an invalid time limit")
+	}
+
+}
+
+func TestRunQuestionLimit(t *testing.T) {
+
+	problems := make([]Problem, len(testProblems))
+
+	copy(problems, testProblems)
+
+	problems[1].TimeLimit = time.Second
+	problems[2].TimeLimit = time.Second
+
+	q := Quiz{Problems: problems, QuestionLimit: 20 * time.Millisecond}
+
+	in, w := io.Pipe()
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		io.WriteString(w, "2\n11\n")
+		w.Close()
+	}()
+
+	result, err := NewSession(q).Run(context.Background(), in, io.Discard)
+
+	if err != nil {
+		t.Fatalf("Run() returned an error: %s", err)
+	}
+
+	checkResult(t, result, Result{Total: 3, Score: 2, MaxScore: 3, Answered: 2, Correct: 2, Expired: 1})
+
+	if len(result.Responses) != 3 || !result.Responses[0].Expired {
+		t.Errorf("Expected the first of three responses to have expired, got %+v", result.Responses)
+	}
+
+}
+
+func TestSlowest(t *testing.T) {
+
+	r := Result{Responses: []Response{
+		{Problem: testProblems[0], Elapsed: time.Second},
+		{Problem: testProblems[1], Elapsed: 3 * time.Second},
+		{Problem: testProblems[2], Elapsed: 2 * time.Second},
+	}}
+
+	slowest := r.Slowest(2)
+
+	if len(slowest) != 2 || slowest[0].Problem.Question != "1+1" || slowest[1].Problem.Question != "8+3" {
+		t.Errorf("Got %+v, want the second and third problems", slowest)
+	}
+
+}
+
+func TestSessionSteps(t *testing.T) {
+
+	s := NewSession(Quiz{Problems: testProblems})
+
+	if p, i, ok := s.Current(); !ok || i != 0 || p.Question != "5+5" {
+		t.Fatalf("Got problem %d (%v), want the first problem", i, p)
+	}
+
+	if r := s.Answer("10", time.Second); !r.Correct || r.Elapsed != time.Second {
+		t.Errorf("Got %+v, want a correct response taking 1s", r)
+	}
+
+	if h := s.Hint(); h != "2" {
+		t.Errorf("Got hint %q, want \"2\"", h)
+	}
+
+	s.Skip(0)
+
+	s.Expire(time.Second)
+
+	if _, _, ok := s.Current(); ok {
+		t.Errorf("Expected the session to have ended")
+	}
+
+	checkResult(t, s.Result(), Result{Total: 3, Score: 1, MaxScore: 3, Answered: 1, Correct: 1, Skipped: 1, Expired: 1, Hints: 1})
+
+}
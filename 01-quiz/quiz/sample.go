@@ -0,0 +1,57 @@
+package quiz
+
+import (
+	"math/rand"
+	"sort"
+)
+
+// Sample returns a copy of the quiz holding n of its problems, picked at
+// random (all of them if n is not positive). The problems keep their original
+// order unless shuffle is set. A given seed always produces the same
+// selection in the same order, so that a session can be reproduced.
+func (q Quiz) Sample(n int, shuffle bool, seed int64) Quiz {
+
+	r := rand.New(rand.NewSource(seed))
+
+	perm := r.Perm(len(q.Problems))
+
+	if n > 0 && n < len(perm) {
+		perm = perm[:n]
+	}
+
+	if !shuffle {
+		sort.Ints(perm)
+	}
+
+	problems := make([]Problem, len(perm))
+
+	for i, j := range perm {
+		problems[i] = q.Problems[j]
+	}
+
+	q.Problems = problems
+
+	return q
+
+}
+
+// Tagged returns a copy of the quiz holding only the problems that have one
+// of the given tags, as their category or among their tags.
+func (q Quiz) Tagged(tags ...string) Quiz {
+
+	var problems []Problem
+
+	for _, p := range q.Problems {
+		for _, t := range tags {
+			if p.HasTag(t) {
+				problems = append(problems, p)
+				break
+			}
+		}
+	}
+
+	q.Problems = problems
+
+	return q
+
+}
@@ -0,0 +1,90 @@
+package quiz
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func questions(q Quiz) []string {
+
+	qs := make([]string, len(q.Problems))
+
+	for i, p := range q.Problems {
+		qs[i] = p.Question
+	}
+
+	return qs
+
+}
+
+func bank(n int) Quiz {
+
+	q := Quiz{}
+
+	for i := 0; i < n; i++ {
+		q.Problems = append(q.Problems, Problem{
+			Question: fmt.Sprintf("%d+%d", i, i),
+			Answers:  []string{fmt.Sprint(i + i)},
+		})
+	}
+
+	return q
+
+}
+
+func TestSampleReproducible(t *testing.T) {
+
+	q := bank(100)
+
+	a := questions(q.Sample(20, true, 42))
+	b := questions(q.Sample(20, true, 42))
+	c := questions(q.Sample(20, true, 43))
+
+	if len(a) != 20 {
+		t.Fatalf("Got %d problems, want 20", len(a))
+	}
+
+	if !reflect.DeepEqual(a, b) {
+		t.Errorf("Expected the same seed to give the same selection, got %v and %v", a, b)
+	}
+
+	if reflect.DeepEqual(a, c) {
+		t.Errorf("Expected different seeds to give different selections")
+	}
+
+}
+
+func TestSampleKeepsOrder(t *testing.T) {
+
+	q := bank(50)
+
+	sample := q.Sample(10, false, 7)
+
+	index := map[string]int{}
+
+	for i, p := range q.Problems {
+		index[p.Question] = i
+	}
+
+	for i := 1; i < len(sample.Problems); i++ {
+		if index[sample.Problems[i-1].Question] > index[sample.Problems[i].Question] {
+			t.Fatalf("Expected an unshuffled sample to keep the original order, got %v", questions(sample))
+		}
+	}
+
+}
+
+func TestSampleAll(t *testing.T) {
+
+	q := bank(10)
+
+	if got := q.Sample(0, false, 1); !reflect.DeepEqual(questions(got), questions(q)) {
+		t.Errorf("Got %v, want the original problems", questions(got))
+	}
+
+	if got := q.Sample(25, true, 1); len(got.Problems) != 10 {
+		t.Errorf("Got %d problems, want 10", len(got.Problems))
+	}
+
+}
@@ -0,0 +1,500 @@
+package race
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/MichaelZalla/gophercises/01-quiz/quiz"
+)
+
+// DefaultQuestionLimit bounds each question in a race whose problems don't
+// have a time limit of their own, so that one idle player can't stall
+// everyone else.
+const DefaultQuestionLimit = 30 * time.Second
+
+// writeTimeout bounds each write to a player, so that one who stops reading
+// can't stall the race for everyone else.
+const writeTimeout = 5 * time.Second
+
+// Host runs a head-to-head quiz for players who connect over TCP, e.g. with
+// nc or Join. Every player gets the same questions at the same time, and a
+// leaderboard is broadcast after each question.
+type Host struct {
+	quiz    quiz.Quiz
+	log     io.Writer
+	inbox   chan message
+	done    chan struct{}
+	mu      sync.Mutex
+	players []*player
+	joined  chan struct{}
+	started bool
+	timeout time.Duration
+}
+
+type player struct {
+	name     string
+	conn     net.Conn
+	session  *quiz.Session
+	elapsed  time.Duration
+	answered bool
+	gone     bool
+}
+
+// message is a line of input from a player, or notice that the player has
+// disconnected.
+type message struct {
+	player *player
+	line   string
+	eof    bool
+}
+
+// Standing is a player's position on the leaderboard. Ties on score are
+// broken by the total time spent answering.
+type Standing struct {
+	Name    string
+	Correct int
+	Score   float64
+	Time    time.Duration
+}
+
+// NewHost prepares a race over the given quiz. Progress is logged to log.
+func NewHost(q quiz.Quiz, log io.Writer) *Host {
+
+	if q.QuestionLimit <= 0 {
+		q.QuestionLimit = DefaultQuestionLimit
+	}
+
+	// Every player must be asked the same question at the same time
+
+	q.Adaptive = false
+
+	q.Ask = 0
+
+	return &Host{
+		quiz:    q,
+		log:     log,
+		inbox:   make(chan message),
+		done:    make(chan struct{}),
+		joined:  make(chan struct{}, 1),
+		timeout: writeTimeout,
+	}
+
+}
+
+// Accept lets players join from ln until the listener is closed. Players who
+// connect once the race has started are turned away.
+func (h *Host) Accept(ln net.Listener) error {
+
+	for {
+
+		conn, err := ln.Accept()
+
+		if err != nil {
+			return err
+		}
+
+		go h.join(conn)
+
+	}
+
+}
+
+// Players returns the number of players who have joined.
+func (h *Host) Players() int {
+
+	h.mu.Lock()
+
+	defer h.mu.Unlock()
+
+	return len(h.players)
+
+}
+
+// WaitFor blocks until at least n players have joined, or ctx is done.
+func (h *Host) WaitFor(ctx context.Context, n int) error {
+
+	for h.Players() < n {
+		select {
+		case <-h.joined:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	return nil
+
+}
+
+func (h *Host) join(conn net.Conn) {
+
+	lines := make(chan string)
+
+	go func() {
+
+		defer close(lines)
+
+		scanner := bufio.NewScanner(conn)
+
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+
+	}()
+
+	fmt.Fprintln(conn, "Welcome to the quiz race! Enter your name:")
+
+	name, ok := <-lines
+
+	name = strings.TrimSpace(name)
+
+	if !ok {
+		conn.Close()
+		return
+	}
+
+	if name == "" {
+		name = conn.RemoteAddr().String()
+	}
+
+	p := &player{
+		name:    name,
+		conn:    conn,
+		session: quiz.NewSession(h.quiz),
+	}
+
+	h.mu.Lock()
+
+	if h.started {
+		h.mu.Unlock()
+		fmt.Fprintln(conn, "Sorry, the race has already started.")
+		conn.Close()
+		return
+	}
+
+	h.players = append(h.players, p)
+
+	count := len(h.players)
+
+	h.mu.Unlock()
+
+	fmt.Fprintf(h.log, "%s joined (%d player(s)).\n", name, count)
+
+	fmt.Fprintf(conn, "Hi %s! Waiting for the race to start...\n", name)
+
+	select {
+	case h.joined <- struct{}{}:
+	default:
+	}
+
+	// Forward everything the player types to the host, until the race is over
+
+	for line := range lines {
+		if !h.forward(message{player: p, line: line}) {
+			return
+		}
+	}
+
+	h.forward(message{player: p, eof: true})
+
+}
+
+func (h *Host) forward(m message) bool {
+
+	select {
+	case h.inbox <- m:
+		return true
+	case <-h.done:
+		return false
+	}
+
+}
+
+// Run asks each question of the quiz to every player who has joined, and
+// returns the final standings. No one may join once Run has been called.
+func (h *Host) Run(ctx context.Context) []Standing {
+
+	h.mu.Lock()
+
+	h.started = true
+
+	players := make([]*player, len(h.players))
+
+	copy(players, h.players)
+
+	h.mu.Unlock()
+
+	defer close(h.done)
+
+	if len(players) == 0 {
+		return nil
+	}
+
+	var timeout <-chan time.Time
+
+	if h.quiz.TimeLimit > 0 {
+
+		timer := time.NewTimer(h.quiz.TimeLimit)
+
+		defer timer.Stop()
+
+		timeout = timer.C
+
+	}
+
+	h.broadcast(players, "The race is on! %d questions, %d players. Answer with a line, or :skip.\n", len(h.quiz.Problems), len(players))
+
+race:
+	for i, p := range h.quiz.Problems {
+
+		h.broadcast(players, "\nProblem #%d: %s = \n", i+1, p.Question)
+
+		for j, c := range p.Choices {
+			h.broadcast(players, "  %c) %s\n", 'a'+j, c)
+		}
+
+		h.discard()
+
+		for _, pl := range players {
+			pl.answered = pl.gone
+		}
+
+		limit := players[0].session.Limit(p)
+
+		timer := time.NewTimer(limit)
+
+		asked := time.Now()
+
+	question:
+		for !everyoneAnswered(players) {
+
+			select {
+
+			case <-ctx.Done():
+				timer.Stop()
+				break race
+
+			case <-timeout:
+				timer.Stop()
+				h.broadcast(players, "Time's up!\n")
+				for _, pl := range players {
+					pl.session.TimeOut()
+				}
+				break race
+
+			case <-timer.C:
+				break question
+
+			case m := <-h.inbox:
+				h.receive(m, time.Since(asked))
+
+			}
+
+		}
+
+		timer.Stop()
+
+		// Anyone who hasn't answered by now has run out of time
+
+		for _, pl := range players {
+			if !pl.answered {
+				pl.session.Expire(limit)
+				pl.elapsed += limit
+				h.send(pl, "Out of time!\n")
+			}
+		}
+
+		h.broadcast(players, "The answer was %s.\n", p.Expected())
+
+		if p.Explanation != "" {
+			h.broadcast(players, "%s\n", p.Explanation)
+		}
+
+		h.broadcast(players, "\n%s", leaderboard(standings(players)))
+
+	}
+
+	final := standings(players)
+
+	h.broadcast(players, "\nFinal standings:\n%s", leaderboard(final))
+
+	for _, pl := range players {
+		pl.conn.Close()
+	}
+
+	return final
+
+}
+
+// discard throws away anything typed between questions, taking note only of
+// players who have left.
+func (h *Host) discard() {
+
+	for {
+		select {
+		case m := <-h.inbox:
+			if m.eof {
+				h.receive(m, 0)
+			}
+		default:
+			return
+		}
+	}
+
+}
+
+// receive handles a line from a player during a question.
+func (h *Host) receive(m message, elapsed time.Duration) {
+
+	pl := m.player
+
+	if m.eof {
+
+		if pl.gone {
+			return
+		}
+
+		pl.gone = true
+		pl.answered = true
+		fmt.Fprintf(h.log, "%s left the race.\n", pl.name)
+		return
+
+	}
+
+	if pl.answered {
+		h.send(pl, "You've already answered this question.\n")
+		return
+	}
+
+	pl.answered = true
+
+	pl.elapsed += elapsed
+
+	if strings.TrimSpace(m.line) == ":skip" {
+		pl.session.Skip(elapsed)
+		h.send(pl, "Skipped.\n")
+		return
+	}
+
+	if pl.session.Answer(m.line, elapsed).Correct {
+		h.send(pl, "Correct!\n")
+	} else {
+		h.send(pl, "Wrong!\n")
+	}
+
+}
+
+func everyoneAnswered(players []*player) bool {
+
+	for _, pl := range players {
+		if !pl.answered {
+			return false
+		}
+	}
+
+	return true
+
+}
+
+// send writes to a single player. A player who can't be written to in time
+// is dropped from the race.
+func (h *Host) send(pl *player, format string, args ...interface{}) {
+
+	if pl.gone {
+		return
+	}
+
+	pl.conn.SetWriteDeadline(time.Now().Add(h.timeout))
+
+	if _, err := fmt.Fprintf(pl.conn, format, args...); err != nil {
+		pl.gone = true
+		pl.answered = true
+		pl.conn.Close()
+		fmt.Fprintf(h.log, "%s was dropped from the race: %s\n", pl.name, err)
+	}
+
+}
+
+func (h *Host) broadcast(players []*player, format string, args ...interface{}) {
+
+	for _, pl := range players {
+		h.send(pl, format, args...)
+	}
+
+	fmt.Fprintf(h.log, format, args...)
+
+}
+
+func standings(players []*player) []Standing {
+
+	s := make([]Standing, len(players))
+
+	for i, pl := range players {
+
+		r := pl.session.Result()
+
+		s[i] = Standing{
+			Name:    pl.name,
+			Correct: r.Correct,
+			Score:   r.Score,
+			Time:    pl.elapsed,
+		}
+
+	}
+
+	sort.SliceStable(s, func(i, j int) bool {
+
+		if s[i].Score != s[j].Score {
+			return s[i].Score > s[j].Score
+		}
+
+		return s[i].Time < s[j].Time
+
+	})
+
+	return s
+
+}
+
+func leaderboard(standings []Standing) string {
+
+	var b strings.Builder
+
+	tw := tabwriter.NewWriter(&b, 0, 4, 2, ' ', 0)
+
+	for i, s := range standings {
+		fmt.Fprintf(tw, "%d.\t%s\t%g points\t%s\n", i+1, s.Name, s.Score, s.Time.Round(time.Millisecond))
+	}
+
+	tw.Flush()
+
+	return b.String()
+
+}
+
+// Join connects to a race hosted at addr, copying in to the host and
+// everything the host sends to out, until the race is over.
+func Join(addr string, in io.Reader, out io.Writer) error {
+
+	conn, err := net.Dial("tcp", addr)
+
+	if err != nil {
+		return err
+	}
+
+	defer conn.Close()
+
+	go io.Copy(conn, in)
+
+	_, err = io.Copy(out, conn)
+
+	return err
+
+}
@@ -0,0 +1,198 @@
+package race
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MichaelZalla/gophercises/01-quiz/quiz"
+)
+
+var testQuiz = quiz.Quiz{
+	Problems: []quiz.Problem{
+		{Question: "5+5", Answers: []string{"10"}},
+		{Question: "1+1", Answers: []string{"2"}},
+	},
+	QuestionLimit: time.Second,
+}
+
+// client plays a race by answering each question with the next of its
+// answers, as soon as the question is asked.
+func client(t *testing.T, addr, name string, answers ...string) <-chan string {
+
+	conn, err := net.Dial("tcp", addr)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	transcript := make(chan string, 1)
+
+	go func() {
+
+		defer conn.Close()
+
+		var b strings.Builder
+
+		fmt.Fprintln(conn, name)
+
+		scanner := bufio.NewScanner(conn)
+
+		for scanner.Scan() {
+
+			line := scanner.Text()
+
+			b.WriteString(line + "\n")
+
+			if strings.HasPrefix(line, "Problem #") && len(answers) > 0 {
+				fmt.Fprintln(conn, answers[0])
+				answers = answers[1:]
+			}
+
+		}
+
+		transcript <- b.String()
+
+	}()
+
+	return transcript
+
+}
+
+func TestRace(t *testing.T) {
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer ln.Close()
+
+	h := NewHost(testQuiz, ioutil.Discard)
+
+	go h.Accept(ln)
+
+	ada := client(t, ln.Addr().String(), "Ada", "10", "2")
+	bob := client(t, ln.Addr().String(), "Bob", "10", ":skip")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+
+	defer cancel()
+
+	if err := h.WaitFor(ctx, 2); err != nil {
+		t.Fatalf("Timed out waiting for players: %s", err)
+	}
+
+	final := h.Run(ctx)
+
+	if len(final) != 2 || final[0].Name != "Ada" || final[0].Correct != 2 || final[1].Correct != 1 {
+		t.Errorf("Got %+v, want Ada ahead of Bob with 2 correct answers to 1", final)
+	}
+
+	for _, transcript := range []string{<-ada, <-bob} {
+		if !strings.Contains(transcript, "Final standings:") || !strings.Contains(transcript, "1.  Ada") {
+			t.Errorf("Expected the final standings to be broadcast, got %s", transcript)
+		}
+	}
+
+}
+
+func TestRaceQuestionLimit(t *testing.T) {
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer ln.Close()
+
+	q := testQuiz
+
+	q.QuestionLimit = 50 * time.Millisecond
+
+	h := NewHost(q, ioutil.Discard)
+
+	go h.Accept(ln)
+
+	// Ada only answers the first question, and sits out the second
+
+	ada := client(t, ln.Addr().String(), "Ada", "10")
+
+	h.WaitFor(context.Background(), 1)
+
+	final := h.Run(context.Background())
+
+	if len(final) != 1 || final[0].Correct != 1 {
+		t.Errorf("Got %+v, want a single correct answer", final)
+	}
+
+	if transcript := <-ada; !strings.Contains(transcript, "Out of time!") {
+		t.Errorf("Expected the second question to run out of time, got %s", transcript)
+	}
+
+}
+
+func TestRaceDropsStalledPlayer(t *testing.T) {
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer ln.Close()
+
+	h := NewHost(testQuiz, ioutil.Discard)
+
+	h.timeout = 50 * time.Millisecond
+
+	go h.Accept(ln)
+
+	ada := client(t, ln.Addr().String(), "Ada", "10", "2")
+
+	// Bob joins over a pipe, then stops reading
+
+	host, bob := net.Pipe()
+
+	defer bob.Close()
+
+	go h.join(host)
+
+	scanner := bufio.NewScanner(bob)
+
+	scanner.Scan()
+
+	fmt.Fprintln(bob, "Bob")
+
+	scanner.Scan()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+
+	defer cancel()
+
+	if err := h.WaitFor(ctx, 2); err != nil {
+		t.Fatalf("Timed out waiting for players: %s", err)
+	}
+
+	final := h.Run(ctx)
+
+	if ctx.Err() != nil {
+		t.Fatal("Expected the race to finish without waiting on Bob")
+	}
+
+	if len(final) != 2 || final[0].Name != "Ada" || final[0].Correct != 2 {
+		t.Errorf("Got %+v, want Ada to win with 2 correct answers", final)
+	}
+
+	if transcript := <-ada; !strings.Contains(transcript, "Final standings:") {
+		t.Errorf("Expected the final standings to be broadcast, got %s", transcript)
+	}
+
+}
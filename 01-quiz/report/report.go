@@ -0,0 +1,293 @@
+package report
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/MichaelZalla/gophercises/01-quiz/quiz"
+)
+
+// Status describes how a question was dealt with.
+type Status string
+
+// Statuses that a question may end up in.
+const (
+	Correct    Status = "correct"
+	Incorrect  Status = "incorrect"
+	Skipped    Status = "skipped"
+	Expired    Status = "expired"
+	Unanswered Status = "unanswered"
+)
+
+// Row is the outcome of a single question.
+type Row struct {
+	Number   int     `json:"number"`
+	Question string  `json:"question"`
+	Given    string  `json:"given"`
+	Expected string  `json:"expected"`
+	Status   Status  `json:"status"`
+	Points   float64 `json:"points"`
+	Seconds  float64 `json:"seconds"`
+}
+
+// Report is a breakdown of a quiz session, question by question.
+type Report struct {
+	Name     string  `json:"name"`
+	Total    int     `json:"total"`
+	Correct  int     `json:"correct"`
+	Score    float64 `json:"score"`
+	MaxScore float64 `json:"max_score"`
+	TimedOut bool    `json:"timed_out"`
+	Seconds  float64 `json:"seconds"`
+	Rows     []Row   `json:"questions"`
+}
+
+// New builds a report on a session of the quiz q. The problems are listed in
+// the order they were asked, followed by any that were never reached.
+func New(name string, q quiz.Quiz, r quiz.Result) Report {
+
+	rep := Report{
+		Name:     name,
+		Total:    r.Total,
+		Correct:  r.Correct,
+		Score:    r.Score,
+		MaxScore: r.MaxScore,
+		TimedOut: r.TimedOut,
+	}
+
+	asked := map[string]int{}
+
+	for _, resp := range r.Responses {
+
+		row := Row{
+			Number:   len(rep.Rows) + 1,
+			Question: resp.Problem.Question,
+			Given:    resp.Answer,
+			Expected: resp.Problem.Expected(),
+			Status:   status(resp),
+			Seconds:  seconds(resp.Elapsed),
+		}
+
+		if resp.Correct {
+			row.Points = resp.Problem.Weight()
+		}
+
+		rep.Seconds += row.Seconds
+
+		rep.Rows = append(rep.Rows, row)
+
+		asked[resp.Problem.Question]++
+
+	}
+
+	for _, p := range q.Problems {
+
+		if len(rep.Rows) >= r.Total {
+			break
+		}
+
+		if asked[p.Question] > 0 {
+			asked[p.Question]--
+			continue
+		}
+
+		rep.Rows = append(rep.Rows, Row{
+			Number:   len(rep.Rows) + 1,
+			Question: p.Question,
+			Expected: p.Expected(),
+			Status:   Unanswered,
+		})
+
+	}
+
+	return rep
+
+}
+
+func status(r quiz.Response) Status {
+
+	switch {
+	case r.Correct:
+		return Correct
+	case r.Skipped:
+		return Skipped
+	case r.Expired:
+		return Expired
+	default:
+		return Incorrect
+	}
+
+}
+
+func seconds(d time.Duration) float64 {
+	return d.Round(time.Millisecond).Seconds()
+}
+
+// WriteText writes the report as a table meant to be read in a terminal.
+func (rep Report) WriteText(w io.Writer) error {
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "#\tQuestion\tGiven\tExpected\tResult\tTime")
+
+	for _, row := range rep.Rows {
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\t%.1fs\n", row.Number, row.Question, row.Given, row.Expected, row.Status, row.Seconds)
+	}
+
+	return tw.Flush()
+
+}
+
+// WriteJSON writes the report as JSON.
+func (rep Report) WriteJSON(w io.Writer) error {
+
+	e := json.NewEncoder(w)
+
+	e.SetIndent("", "  ")
+
+	return e.Encode(rep)
+
+}
+
+// WriteCSV writes the rows of the report as CSV, with a header record.
+func (rep Report) WriteCSV(w io.Writer) error {
+
+	cw := csv.NewWriter(w)
+
+	cw.Write([]string{"number", "question", "given", "expected", "status", "points", "seconds"})
+
+	for _, row := range rep.Rows {
+		cw.Write([]string{
+			strconv.Itoa(row.Number),
+			row.Question,
+			row.Given,
+			row.Expected,
+			string(row.Status),
+			strconv.FormatFloat(row.Points, 'g', -1, 64),
+			strconv.FormatFloat(row.Seconds, 'f', 3, 64),
+		})
+	}
+
+	cw.Flush()
+
+	return cw.Error()
+
+}
+
+type junitSuites struct {
+	XMLName xml.Name     `xml:"testsuites"`
+	Suites  []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name     string      `xml:"name,attr"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Skipped  int         `xml:"skipped,attr"`
+	Time     string      `xml:"time,attr"`
+	Cases    []junitCase `xml:"testcase"`
+}
+
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+}
+
+// WriteJUnit writes the report as JUnit XML, with one test case per
+// question. Incorrect answers are failures, while skipped, expired and
+// unanswered questions are reported as skipped.
+func (rep Report) WriteJUnit(w io.Writer) error {
+
+	suite := junitSuite{
+		Name:  rep.Name,
+		Tests: len(rep.Rows),
+		Time:  strconv.FormatFloat(rep.Seconds, 'f', 3, 64),
+	}
+
+	for _, row := range rep.Rows {
+
+		c := junitCase{
+			Name:      fmt.Sprintf("Problem #%d: %s", row.Number, row.Question),
+			ClassName: rep.Name,
+			Time:      strconv.FormatFloat(row.Seconds, 'f', 3, 64),
+		}
+
+		switch row.Status {
+		case Correct:
+		case Incorrect:
+			suite.Failures++
+			c.Failure = &junitMessage{fmt.Sprintf("expected %q, got %q", row.Expected, row.Given)}
+		default:
+			suite.Skipped++
+			c.Skipped = &junitMessage{string(row.Status)}
+		}
+
+		suite.Cases = append(suite.Cases, c)
+
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	e := xml.NewEncoder(w)
+
+	e.Indent("", "  ")
+
+	if err := e.Encode(junitSuites{Suites: []junitSuite{suite}}); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+
+	return err
+
+}
+
+// Save writes the report to the file at path. The format is chosen by the
+// file's extension: .json, .csv and .xml (JUnit) are understood.
+func (rep Report) Save(path string) error {
+
+	var write func(io.Writer) error
+
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".json":
+		write = rep.WriteJSON
+	case ".csv":
+		write = rep.WriteCSV
+	case ".xml":
+		write = rep.WriteJUnit
+	default:
+		return fmt.Errorf("unsupported report file extension '%s'", ext)
+	}
+
+	f, err := os.Create(path)
+
+	if err != nil {
+		return err
+	}
+
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+
+}
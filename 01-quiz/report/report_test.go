@@ -0,0 +1,117 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MichaelZalla/gophercises/01-quiz/quiz"
+)
+
+func testReport() Report {
+
+	problems := []quiz.Problem{
+		{Question: "5+5", Answers: []string{"10"}},
+		{Question: "1+1", Answers: []string{"2"}, Points: 2},
+		{Question: "8+3", Answers: []string{"11"}},
+		{Question: "1+2", Answers: []string{"3"}},
+	}
+
+	result := quiz.Result{
+		Total:    4,
+		Correct:  1,
+		Score:    2,
+		MaxScore: 5,
+		TimedOut: true,
+		Responses: []quiz.Response{
+			{Problem: problems[0], Answer: "11", Elapsed: time.Second},
+			{Problem: problems[1], Answer: "2", Correct: true, Elapsed: 2 * time.Second},
+			{Problem: problems[2], Skipped: true, Elapsed: 500 * time.Millisecond},
+		},
+	}
+
+	return New("problems.csv", quiz.Quiz{Problems: problems}, result)
+
+}
+
+func TestNew(t *testing.T) {
+
+	rep := testReport()
+
+	want := []Status{Incorrect, Correct, Skipped, Unanswered}
+
+	if len(rep.Rows) != len(want) {
+		t.Fatalf("Got %d rows, want %d", len(rep.Rows), len(want))
+	}
+
+	for i, row := range rep.Rows {
+		if row.Status != want[i] {
+			t.Errorf("Row %d: got status %s, want %s", i+1, row.Status, want[i])
+		}
+	}
+
+	if rep.Rows[1].Points != 2 || rep.Seconds != 3.5 {
+		t.Errorf("Got %g points and %gs, want 2 points and 3.5s", rep.Rows[1].Points, rep.Seconds)
+	}
+
+}
+
+func TestWriteJSON(t *testing.T) {
+
+	var buf bytes.Buffer
+
+	if err := testReport().WriteJSON(&buf); err != nil {
+		t.Fatalf("WriteJSON() returned an error: %s", err)
+	}
+
+	var rep Report
+
+	if err := json.Unmarshal(buf.Bytes(), &rep); err != nil {
+		t.Fatalf("Failed to decode the JSON report: %s", err)
+	}
+
+	if len(rep.Rows) != 4 || rep.Rows[0].Given != "11" || rep.Rows[0].Expected != "10" {
+		t.Errorf("Got %+v, want the given and expected answers for each question", rep.Rows)
+	}
+
+}
+
+func TestWriteCSV(t *testing.T) {
+
+	var buf bytes.Buffer
+
+	if err := testReport().WriteCSV(&buf); err != nil {
+		t.Fatalf("WriteCSV() returned an error: %s", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+
+	if len(lines) != 5 || lines[1] != "1,5+5,11,10,incorrect,0,1.000" {
+		t.Errorf("Got %q, want a header and four rows", lines)
+	}
+
+}
+
+func TestWriteJUnit(t *testing.T) {
+
+	var buf bytes.Buffer
+
+	if err := testReport().WriteJUnit(&buf); err != nil {
+		t.Fatalf("WriteJUnit() returned an error: %s", err)
+	}
+
+	out := buf.String()
+
+	for _, s := range []string{
+		`<testsuite name="problems.csv" tests="4" failures="1" skipped="2" time="3.500">`,
+		`<failure message="expected &#34;10&#34;, got &#34;11&#34;"></failure>`,
+		`<skipped message="unanswered"></skipped>`,
+	} {
+		if !strings.Contains(out, s) {
+			t.Errorf("Expected %q in the output, got %q", s, out)
+		}
+	}
+
+}
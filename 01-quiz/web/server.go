@@ -0,0 +1,518 @@
+package web
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"html/template"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/MichaelZalla/gophercises/01-quiz/quiz"
+	"github.com/MichaelZalla/gophercises/01-quiz/report"
+)
+
+const cookieName = "quiz_session"
+
+const (
+	// DefaultLearnerTTL is how long a learner's attempt is kept after their
+	// last request
+	DefaultLearnerTTL = 24 * time.Hour
+	// maxLearners bounds the number of attempts kept at once
+	maxLearners = 10000
+)
+
+// Server runs a quiz over HTTP, one question per page. Each learner's
+// progress, score and timers are kept on the server.
+type Server struct {
+	name       string
+	quiz       quiz.Quiz
+	adminToken string
+	learnerTTL time.Duration
+	mux        *http.ServeMux
+	mu         sync.Mutex
+	learners   map[string]*learner
+}
+
+// learner is a single learner's attempt at the quiz.
+type learner struct {
+	name     string
+	started  time.Time
+	asked    time.Time
+	seen     time.Time
+	session  *quiz.Session
+	previous *quiz.Response
+}
+
+// ServerOption configures a Server.
+type ServerOption func(s *Server)
+
+// WithAdminToken requires the given token in order to view the admin page,
+// either as a bearer token or, so that a browser can prompt for it, as the
+// password of HTTP basic auth. Without a token, the admin page is not served
+// at all.
+func WithAdminToken(token string) ServerOption {
+	return func(s *Server) {
+		s.adminToken = token
+	}
+}
+
+// WithLearnerTTL replaces how long a learner's attempt is kept after their
+// last request (DefaultLearnerTTL).
+func WithLearnerTTL(ttl time.Duration) ServerOption {
+	return func(s *Server) {
+		s.learnerTTL = ttl
+	}
+}
+
+// NewServer returns a Server for the given quiz. The name identifies the
+// quiz on each page.
+func NewServer(name string, q quiz.Quiz, opts ...ServerOption) *Server {
+
+	s := &Server{
+		name:       name,
+		quiz:       q,
+		mux:        http.NewServeMux(),
+		learners:   map[string]*learner{},
+		learnerTTL: DefaultLearnerTTL,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	s.mux.HandleFunc("/", s.handleIndex)
+	s.mux.HandleFunc("/start", s.handleStart)
+	s.mux.HandleFunc("/question", s.handleQuestion)
+	s.mux.HandleFunc("/answer", s.handleAnswer)
+	s.mux.HandleFunc("/result", s.handleResult)
+	s.mux.HandleFunc("/admin", s.handleAdmin)
+
+	return s
+
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *Server) handleIndex(w http.ResponseWriter, r *http.Request) {
+
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+
+	render(w, indexTemplate, map[string]interface{}{
+		"Name":  s.name,
+		"Total": len(s.quiz.Problems),
+		"Limit": s.quiz.TimeLimit,
+	})
+
+}
+
+func (s *Server) handleStart(w http.ResponseWriter, r *http.Request) {
+
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed.", http.StatusMethodNotAllowed)
+		return
+	}
+
+	name := strings.TrimSpace(r.FormValue("name"))
+
+	if name == "" {
+		name = "Anonymous"
+	}
+
+	id, err := newID()
+
+	if err != nil {
+		log.Printf("%v", err)
+		http.Error(w, "Something went wrong!", http.StatusInternalServerError)
+		return
+	}
+
+	now := time.Now()
+
+	s.mu.Lock()
+
+	s.prune(now)
+
+	if len(s.learners) >= maxLearners {
+		s.mu.Unlock()
+		http.Error(w, "Too many people are taking the quiz. Please try again later.", http.StatusServiceUnavailable)
+		return
+	}
+
+	s.learners[id] = &learner{
+		name:    name,
+		started: now,
+		asked:   now,
+		seen:    now,
+		session: quiz.NewSession(s.quiz),
+	}
+
+	s.mu.Unlock()
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     cookieName,
+		Value:    id,
+		Path:     "/",
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	http.Redirect(w, r, "/question", http.StatusSeeOther)
+
+}
+
+func (s *Server) handleQuestion(w http.ResponseWriter, r *http.Request) {
+
+	s.mu.Lock()
+
+	defer s.mu.Unlock()
+
+	l, ok := s.learner(r)
+
+	if !ok {
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+		return
+	}
+
+	s.tick(l, time.Now())
+
+	p, i, ok := l.session.Current()
+
+	if !ok {
+		http.Redirect(w, r, "/result", http.StatusSeeOther)
+		return
+	}
+
+	// Refresh the page when the nearest time limit runs out, so that the
+	// learner doesn't sit on a question that has already expired
+
+	remaining := s.remaining(l, p, time.Now())
+
+	data := map[string]interface{}{
+		"Name":     s.name,
+		"Number":   i + 1,
+		"Total":    l.session.Result().Total,
+		"Problem":  p,
+		"Choices":  choices(p),
+		"Previous": l.previous,
+	}
+
+	if remaining > 0 {
+		data["Remaining"] = remaining.Round(time.Second)
+		data["Refresh"] = int(remaining.Seconds()) + 1
+	}
+
+	render(w, questionTemplate, data)
+
+}
+
+func (s *Server) handleAnswer(w http.ResponseWriter, r *http.Request) {
+
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed.", http.StatusMethodNotAllowed)
+		return
+	}
+
+	s.mu.Lock()
+
+	defer s.mu.Unlock()
+
+	l, ok := s.learner(r)
+
+	if !ok {
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+		return
+	}
+
+	now := time.Now()
+
+	// Only accept the answer if it arrived in time
+
+	if s.tick(l, now) {
+		http.Redirect(w, r, "/question", http.StatusSeeOther)
+		return
+	}
+
+	// Ignore an answer to any question but the current one, such as a
+	// second submission of the same form
+
+	_, i, _ := l.session.Current()
+
+	if number, err := strconv.Atoi(r.FormValue("number")); err != nil || number != i+1 {
+		http.Redirect(w, r, "/question", http.StatusSeeOther)
+		return
+	}
+
+	elapsed := now.Sub(l.asked)
+
+	var resp quiz.Response
+
+	if r.FormValue("skip") != "" {
+		resp = l.session.Skip(elapsed)
+	} else {
+		resp = l.session.Answer(r.FormValue("answer"), elapsed)
+	}
+
+	l.previous = &resp
+
+	l.asked = now
+
+	http.Redirect(w, r, "/question", http.StatusSeeOther)
+
+}
+
+func (s *Server) handleResult(w http.ResponseWriter, r *http.Request) {
+
+	s.mu.Lock()
+
+	defer s.mu.Unlock()
+
+	l, ok := s.learner(r)
+
+	if !ok {
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+		return
+	}
+
+	s.tick(l, time.Now())
+
+	result := l.session.Result()
+
+	render(w, resultTemplate, map[string]interface{}{
+		"Name":     s.name,
+		"Result":   result,
+		"Report":   report.New(s.name, s.quiz, result),
+		"Previous": l.previous,
+	})
+
+}
+
+// authorized reports whether r carries the admin token, as a bearer token or
+// as a basic auth password.
+func (s *Server) authorized(r *http.Request) bool {
+
+	token := ""
+
+	if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+		token = strings.TrimPrefix(auth, "Bearer ")
+	} else if _, password, ok := r.BasicAuth(); ok {
+		token = password
+	}
+
+	return subtle.ConstantTimeCompare([]byte(token), []byte(s.adminToken)) == 1
+
+}
+
+// score is a single learner's standing, as shown on the admin page.
+type score struct {
+	Name     string
+	Started  time.Time
+	Progress int
+	Total    int
+	Correct  int
+	Score    float64
+	MaxScore float64
+	Status   string
+}
+
+func (s *Server) handleAdmin(w http.ResponseWriter, r *http.Request) {
+
+	if s.adminToken == "" {
+		http.NotFound(w, r)
+		return
+	}
+
+	if !s.authorized(r) {
+		w.Header().Set("WWW-Authenticate", `Basic realm="quiz admin"`)
+		http.Error(w, "Unauthorized.", http.StatusUnauthorized)
+		return
+	}
+
+	s.mu.Lock()
+
+	now := time.Now()
+
+	scores := make([]score, 0, len(s.learners))
+
+	for _, l := range s.learners {
+
+		s.tick(l, now)
+
+		result := l.session.Result()
+
+		sc := score{
+			Name:     l.name,
+			Started:  l.started,
+			Progress: len(result.Responses),
+			Total:    result.Total,
+			Correct:  result.Correct,
+			Score:    result.Score,
+			MaxScore: result.MaxScore,
+			Status:   "in progress",
+		}
+
+		if _, _, ok := l.session.Current(); !ok {
+			sc.Status = "finished"
+		}
+
+		if result.TimedOut {
+			sc.Status = "timed out"
+		}
+
+		scores = append(scores, sc)
+
+	}
+
+	s.mu.Unlock()
+
+	sort.Slice(scores, func(i, j int) bool {
+
+		if scores[i].Score != scores[j].Score {
+			return scores[i].Score > scores[j].Score
+		}
+
+		return scores[i].Started.Before(scores[j].Started)
+
+	})
+
+	render(w, adminTemplate, map[string]interface{}{
+		"Name":   s.name,
+		"Scores": scores,
+	})
+
+}
+
+// learner looks up the learner making a request. The caller must hold s.mu.
+func (s *Server) learner(r *http.Request) (*learner, bool) {
+
+	c, err := r.Cookie(cookieName)
+
+	if err != nil {
+		return nil, false
+	}
+
+	l, ok := s.learners[c.Value]
+
+	if ok {
+		l.seen = time.Now()
+	}
+
+	return l, ok
+
+}
+
+// prune forgets the learners who haven't made a request within the TTL.
+// The caller must hold s.mu.
+func (s *Server) prune(now time.Time) {
+
+	for id, l := range s.learners {
+		if now.Sub(l.seen) > s.learnerTTL {
+			delete(s.learners, id)
+		}
+	}
+
+}
+
+// tick applies any time limits that have run out by now, and reports whether
+// the question that the learner was on is no longer open. The caller must
+// hold s.mu.
+func (s *Server) tick(l *learner, now time.Time) bool {
+
+	p, _, ok := l.session.Current()
+
+	if !ok {
+		return true
+	}
+
+	if s.quiz.TimeLimit > 0 && now.Sub(l.started) >= s.quiz.TimeLimit {
+		l.session.TimeOut()
+		return true
+	}
+
+	if limit := l.session.Limit(p); limit > 0 && now.Sub(l.asked) >= limit {
+
+		resp := l.session.Expire(limit)
+
+		l.previous = &resp
+
+		l.asked = l.asked.Add(limit)
+
+		// Several questions may have expired since the learner last checked in
+
+		s.tick(l, now)
+
+		return true
+
+	}
+
+	return false
+
+}
+
+// remaining returns the time left before the nearest limit runs out, or zero
+// if neither the quiz nor the problem has a limit.
+func (s *Server) remaining(l *learner, p quiz.Problem, now time.Time) time.Duration {
+
+	var remaining time.Duration
+
+	if s.quiz.TimeLimit > 0 {
+		remaining = s.quiz.TimeLimit - now.Sub(l.started)
+	}
+
+	if limit := l.session.Limit(p); limit > 0 {
+		if left := limit - now.Sub(l.asked); remaining == 0 || left < remaining {
+			remaining = left
+		}
+	}
+
+	return remaining
+
+}
+
+type choice struct {
+	Letter string
+	Text   string
+}
+
+func choices(p quiz.Problem) []choice {
+
+	cs := make([]choice, len(p.Choices))
+
+	for i, c := range p.Choices {
+		cs[i] = choice{string(rune('a' + i)), c}
+	}
+
+	return cs
+
+}
+
+func newID() (string, error) {
+
+	b := make([]byte, 16)
+
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+
+}
+
+func render(w http.ResponseWriter, t *template.Template, data interface{}) {
+
+	if err := t.Execute(w, data); err != nil {
+		log.Printf("%v", err)
+		http.Error(w, "Something went wrong!", http.StatusInternalServerError)
+	}
+
+}
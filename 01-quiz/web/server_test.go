@@ -0,0 +1,267 @@
+package web
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MichaelZalla/gophercises/01-quiz/quiz"
+)
+
+var testQuiz = quiz.Quiz{
+	Problems: []quiz.Problem{
+		{Question: "5+5", Answers: []string{"10"}},
+		{Question: "Capital of France?", Answers: []string{"Paris"}, Choices: []string{"Berlin", "Paris"}},
+		{Question: "8+3", Answers: []string{"11"}},
+	},
+	Match: quiz.Numeric(0),
+}
+
+func newClient(t *testing.T) *http.Client {
+
+	jar, err := cookiejar.New(nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &http.Client{Jar: jar}
+
+}
+
+func get(t *testing.T, c *http.Client, u string) string {
+
+	t.Helper()
+
+	resp, err := c.Get(u)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer resp.Body.Close()
+
+	body, _ := ioutil.ReadAll(resp.Body)
+
+	return string(body)
+
+}
+
+func getAdmin(t *testing.T, ts *httptest.Server, auth string) string {
+
+	t.Helper()
+
+	req, err := http.NewRequest("GET", ts.URL+"/admin", nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if auth != "" {
+		req.Header.Set("Authorization", auth)
+	}
+
+	resp, err := ts.Client().Do(req)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer resp.Body.Close()
+
+	body, _ := ioutil.ReadAll(resp.Body)
+
+	return string(body)
+
+}
+
+func post(t *testing.T, c *http.Client, u string, form url.Values) string {
+
+	t.Helper()
+
+	resp, err := c.PostForm(u, form)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer resp.Body.Close()
+
+	body, _ := ioutil.ReadAll(resp.Body)
+
+	return string(body)
+
+}
+
+func TestServer(t *testing.T) {
+
+	ts := httptest.NewServer(NewServer("Test quiz", testQuiz, WithAdminToken("secret")))
+
+	defer ts.Close()
+
+	c := newClient(t)
+
+	page := post(t, c, ts.URL+"/start", url.Values{"name": {"Ada"}})
+
+	if !strings.Contains(page, "Question 1 of 3") || !strings.Contains(page, "5&#43;5") {
+		t.Fatalf("Expected the first question, got %s", page)
+	}
+
+	if !strings.Contains(page, `name="number" value="1"`) {
+		t.Fatalf("Expected the form to hold the question number, got %s", page)
+	}
+
+	page = post(t, c, ts.URL+"/answer", url.Values{"number": {"1"}, "answer": {"10.0"}})
+
+	if !strings.Contains(page, "Correct!") || !strings.Contains(page, "b) Paris") {
+		t.Fatalf("Expected the second question with feedback, got %s", page)
+	}
+
+	// A second submission of the first question's form is ignored
+
+	page = post(t, c, ts.URL+"/answer", url.Values{"number": {"1"}, "answer": {"Paris"}})
+
+	if !strings.Contains(page, "Question 2 of 3") {
+		t.Fatalf("Expected to stay on the second question, got %s", page)
+	}
+
+	post(t, c, ts.URL+"/answer", url.Values{"number": {"2"}, "answer": {"a"}})
+
+	page = post(t, c, ts.URL+"/answer", url.Values{"number": {"3"}, "skip": {"1"}})
+
+	if !strings.Contains(page, "You scored 1 out of 3.") {
+		t.Fatalf("Expected the result page, got %s", page)
+	}
+
+	for _, auth := range []string{"", "Bearer wrong", "secret", "Bearer "} {
+		if page := getAdmin(t, ts, auth); !strings.Contains(page, "Unauthorized") {
+			t.Errorf("Expected the admin page to reject %q, got %s", auth, page)
+		}
+	}
+
+	if page := get(t, ts.Client(), ts.URL+"/admin?token=secret"); !strings.Contains(page, "Unauthorized") {
+		t.Errorf("Expected the admin page to ignore a token in the query, got %s", page)
+	}
+
+	page = getAdmin(t, ts, "Bearer secret")
+
+	if !strings.Contains(page, "<td>Ada</td>") || !strings.Contains(page, "<td>3 / 3</td>") || !strings.Contains(page, "finished") {
+		t.Errorf("Expected Ada's finished score on the admin page, got %s", page)
+	}
+
+}
+
+func TestServerQuestionLimit(t *testing.T) {
+
+	q := testQuiz
+
+	q.QuestionLimit = 20 * time.Millisecond
+
+	ts := httptest.NewServer(NewServer("Test quiz", q))
+
+	defer ts.Close()
+
+	c := newClient(t)
+
+	post(t, c, ts.URL+"/start", url.Values{"name": {"Grace"}})
+
+	time.Sleep(30 * time.Millisecond)
+
+	page := post(t, c, ts.URL+"/answer", url.Values{"answer": {"10"}})
+
+	if !strings.Contains(page, "Out of time!") || !strings.Contains(page, "Question 2 of 3") {
+		t.Errorf("Expected the first question to have expired, got %s", page)
+	}
+
+}
+
+func TestServerAdminDisabled(t *testing.T) {
+
+	ts := httptest.NewServer(NewServer("Test quiz", testQuiz))
+
+	defer ts.Close()
+
+	resp, err := ts.Client().Get(ts.URL + "/admin")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Got status %d, want the admin page to be disabled without a token", resp.StatusCode)
+	}
+
+}
+
+func TestServerPrunesLearners(t *testing.T) {
+
+	s := NewServer("Test quiz", testQuiz, WithLearnerTTL(10*time.Millisecond))
+
+	ts := httptest.NewServer(s)
+
+	defer ts.Close()
+
+	post(t, newClient(t), ts.URL+"/start", url.Values{"name": {"Ada"}})
+
+	time.Sleep(20 * time.Millisecond)
+
+	post(t, newClient(t), ts.URL+"/start", url.Values{"name": {"Grace"}})
+
+	s.mu.Lock()
+
+	defer s.mu.Unlock()
+
+	if len(s.learners) != 1 {
+		t.Errorf("Got %d learners, want only the recent one", len(s.learners))
+	}
+
+}
+
+func TestServerWithoutSession(t *testing.T) {
+
+	ts := httptest.NewServer(NewServer("Test quiz", testQuiz))
+
+	defer ts.Close()
+
+	page := get(t, newClient(t), ts.URL+"/question")
+
+	if !strings.Contains(page, `action="/start"`) {
+		t.Errorf("Expected to be sent to the start page, got %s", page)
+	}
+
+}
+
+func TestServerAdminBasicAuth(t *testing.T) {
+
+	ts := httptest.NewServer(NewServer("Test quiz", testQuiz, WithAdminToken("secret")))
+
+	defer ts.Close()
+
+	req, err := http.NewRequest("GET", ts.URL+"/admin", nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req.SetBasicAuth("admin", "secret")
+
+	resp, err := ts.Client().Do(req)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Got status %d, want the admin page for a basic auth password", resp.StatusCode)
+	}
+
+}
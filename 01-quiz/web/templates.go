@@ -0,0 +1,94 @@
+package web
+
+import "html/template"
+
+var layout = `
+	<!DOCTYPE html>
+	<html lang="en">
+	<head>
+		<meta charset="UTF-8">
+		<meta name="viewport" content="width=device-width, initial-scale=1.0">
+		{{ block "head" . }}{{ end }}
+		<title>{{ .Name }}</title>
+	</head>
+	<body>
+		<section class="page">
+			<h1>{{ .Name }}</h1>
+			{{ template "content" . }}
+		</section>
+	</body>
+	</html>`
+
+var previous = `
+	{{ define "previous" }}
+		{{ with .Previous }}
+			<p class="previous">
+				{{ if .Correct }}Correct!{{ else if .Skipped }}Skipped.{{ else if .Expired }}Out of time!{{ else }}Not quite: the answer was {{ .Problem.Expected }}.{{ end }}
+				{{ .Problem.Explanation }}
+			</p>
+		{{ end }}
+	{{ end }}`
+
+var indexTemplate = page(`
+	{{ define "content" }}
+		<p>{{ .Total }} questions{{ if .Limit }}, {{ .Limit }} in total{{ end }}.</p>
+		<form method="POST" action="/start">
+			<label>Your name <input name="name" autofocus></label>
+			<button type="submit">Start</button>
+		</form>
+	{{ end }}`)
+
+var questionTemplate = page(`
+	{{ define "head" }}
+		{{ with .Refresh }}<meta http-equiv="refresh" content="{{ . }}">{{ end }}
+	{{ end }}
+	{{ define "content" }}
+		{{ template "previous" . }}
+		<h2>Question {{ .Number }} of {{ .Total }}</h2>
+		{{ with .Remaining }}<p class="timer">{{ . }} left</p>{{ end }}
+		<p>{{ .Problem.Question }}</p>
+		<form method="POST" action="/answer">
+			<input type="hidden" name="number" value="{{ .Number }}">
+			{{ if .Choices }}
+				{{ range .Choices }}
+					<label><input type="radio" name="answer" value="{{ .Letter }}"> {{ .Letter }}) {{ .Text }}</label><br>
+				{{ end }}
+			{{ else }}
+				<input name="answer" autocomplete="off" autofocus>
+			{{ end }}
+			<button type="submit">Answer</button>
+			<button type="submit" name="skip" value="1">Skip</button>
+		</form>
+	{{ end }}`)
+
+var resultTemplate = page(`
+	{{ define "content" }}
+		{{ template "previous" . }}
+		<h2>{{ .Result }}</h2>
+		<table>
+			<tr><th>#</th><th>Question</th><th>Given</th><th>Expected</th><th>Result</th><th>Time</th></tr>
+			{{ range .Report.Rows }}
+				<tr><td>{{ .Number }}</td><td>{{ .Question }}</td><td>{{ .Given }}</td><td>{{ .Expected }}</td><td>{{ .Status }}</td><td>{{ printf "%.1f" .Seconds }}s</td></tr>
+			{{ end }}
+		</table>
+	{{ end }}`)
+
+var adminTemplate = page(`
+	{{ define "head" }}
+		<meta http-equiv="refresh" content="5">
+	{{ end }}
+	{{ define "content" }}
+		<h2>Live scores</h2>
+		<table>
+			<tr><th>Name</th><th>Started</th><th>Progress</th><th>Correct</th><th>Score</th><th>Status</th></tr>
+			{{ range .Scores }}
+				<tr><td>{{ .Name }}</td><td>{{ .Started.Format "15:04:05" }}</td><td>{{ .Progress }} / {{ .Total }}</td><td>{{ .Correct }}</td><td>{{ .Score }} / {{ .MaxScore }}</td><td>{{ .Status }}</td></tr>
+			{{ else }}
+				<tr><td colspan="6">Nobody has started the quiz yet.</td></tr>
+			{{ end }}
+		</table>
+	{{ end }}`)
+
+func page(content string) *template.Template {
+	return template.Must(template.Must(template.New("").Parse(layout + previous)).Parse(content))
+}
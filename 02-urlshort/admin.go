@@ -0,0 +1,391 @@
+package urlshort
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"log"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// AdminPrefix is the path beneath which an Admin serves its API.
+const AdminPrefix = "/_admin/"
+
+const (
+	base62      = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+	slugLength  = 6
+	slugTries   = 10
+	topReferers = 10
+)
+
+// Admin serves a JSON API for managing the links in a Store:
+//
+//	GET    /_admin/links         lists every link
+//	POST   /_admin/links         creates a link from {"url": ..., "slug": ...}
+//	GET    /_admin/links/{slug}  returns a link
+//	PUT    /_admin/links/{slug}  adds or replaces a link from {"url": ...}
+//	DELETE /_admin/links/{slug}  removes a link
+//	GET    /_admin/stats/{slug}  summarizes the hits on a link
+//
+// The slug is optional when creating a link; a random one is chosen if it
+// is left out. Either kind of request may also set "created", which
+// otherwise defaults to now. Stats are only served if the Admin was given a HitLog.
+//
+// Every request must carry the Admin's token in an "Authorization: Bearer"
+// header. An Admin without a token refuses every request.
+type Admin struct {
+	store Store
+	hits  HitLog
+	token string
+	mux   *http.ServeMux
+}
+
+// AdminOption configures an Admin.
+type AdminOption func(a *Admin)
+
+// WithHits serves stats from the given HitLog.
+func WithHits(hits HitLog) AdminOption {
+	return func(a *Admin) {
+		a.hits = hits
+	}
+}
+
+// WithAdminToken sets the token that requests must carry.
+func WithAdminToken(token string) AdminOption {
+	return func(a *Admin) {
+		a.token = token
+	}
+}
+
+// newLink is the body of a request to create a link.
+type newLink struct {
+	URL     string    `json:"url"`
+	Slug    string    `json:"slug"`
+	Created time.Time `json:"created"`
+}
+
+// NewAdmin returns an Admin for the given store.
+func NewAdmin(store Store, opts ...AdminOption) *Admin {
+
+	a := &Admin{
+		store: store,
+		mux:   http.NewServeMux(),
+	}
+
+	for _, opt := range opts {
+		opt(a)
+	}
+
+	a.mux.HandleFunc(AdminPrefix+"links", a.handleLinks)
+	a.mux.HandleFunc(AdminPrefix+"links/", a.handleLink)
+
+	if a.hits != nil {
+		a.mux.HandleFunc(AdminPrefix+"stats/", a.handleStats)
+	}
+
+	return a
+
+}
+
+func (a *Admin) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+
+	if a.token == "" {
+		writeError(w, http.StatusForbidden, "the admin API is disabled")
+		return
+	}
+
+	auth := r.Header.Get("Authorization")
+
+	token := strings.TrimPrefix(auth, "Bearer ")
+
+	if token == auth || subtle.ConstantTimeCompare([]byte(token), []byte(a.token)) != 1 {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		writeError(w, http.StatusUnauthorized, "a valid admin token is required")
+		return
+	}
+
+	a.mux.ServeHTTP(w, r)
+
+}
+
+func (a *Admin) handleLinks(w http.ResponseWriter, r *http.Request) {
+
+	switch r.Method {
+	case http.MethodGet:
+		a.list(w, r)
+	case http.MethodPost:
+		a.create(w, r)
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+
+}
+
+func (a *Admin) handleLink(w http.ResponseWriter, r *http.Request) {
+
+	path := "/" + strings.TrimPrefix(r.URL.Path, AdminPrefix+"links/")
+
+	switch r.Method {
+	case http.MethodGet:
+		a.get(w, r, path)
+	case http.MethodPut:
+		a.replace(w, r, path)
+	case http.MethodDelete:
+		a.remove(w, r, path)
+	default:
+		w.Header().Set("Allow", "GET, PUT, DELETE")
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+
+}
+
+func (a *Admin) get(w http.ResponseWriter, r *http.Request, path string) {
+
+	link, ok, err := a.store.Lookup(path)
+
+	if err != nil {
+		log.Printf("%v", err)
+		writeError(w, http.StatusInternalServerError, "something went wrong")
+		return
+	}
+
+	if !ok {
+		writeError(w, http.StatusNotFound, "no link with path '"+path+"'")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, link)
+
+}
+
+// replace adds or replaces the link at a path, which (unlike a slug) may
+// hold several segments, as links imported from a file can. Each segment
+// must still be a valid slug.
+func (a *Admin) replace(w http.ResponseWriter, r *http.Request, path string) {
+
+	var link Link
+
+	if err := json.NewDecoder(r.Body).Decode(&link); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON: "+err.Error())
+		return
+	}
+
+	link.Path = path
+
+	if link.Created.IsZero() {
+		link.Created = time.Now()
+	}
+
+	if err := checkLink(link); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := a.store.Put(link); err != nil {
+		log.Printf("%v", err)
+		writeError(w, http.StatusInternalServerError, "something went wrong")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, link)
+
+}
+
+func (a *Admin) remove(w http.ResponseWriter, r *http.Request, path string) {
+
+	err := a.store.Delete(path)
+
+	if err == ErrNotFound {
+		writeError(w, http.StatusNotFound, "no link with path '"+path+"'")
+		return
+	}
+
+	if err != nil {
+		log.Printf("%v", err)
+		writeError(w, http.StatusInternalServerError, "something went wrong")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+
+}
+
+func (a *Admin) handleStats(w http.ResponseWriter, r *http.Request) {
+
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", "GET")
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	path := "/" + strings.TrimPrefix(r.URL.Path, AdminPrefix+"stats/")
+
+	hits, err := a.hits.Hits(path)
+
+	if err != nil {
+		log.Printf("%v", err)
+		writeError(w, http.StatusInternalServerError, "something went wrong")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, Summarize(path, hits, topReferers))
+
+}
+
+func (a *Admin) list(w http.ResponseWriter, r *http.Request) {
+
+	links, err := a.store.List()
+
+	if err != nil {
+		log.Printf("%v", err)
+		writeError(w, http.StatusInternalServerError, "something went wrong")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, links)
+
+}
+
+func (a *Admin) create(w http.ResponseWriter, r *http.Request) {
+
+	var body newLink
+
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON: "+err.Error())
+		return
+	}
+
+	if err := checkURL(body.URL); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	link := Link{URL: body.URL, Created: body.Created}
+
+	if link.Created.IsZero() {
+		link.Created = time.Now()
+	}
+
+	var err error
+
+	if body.Slug != "" {
+
+		if !validSlug(body.Slug) {
+			writeError(w, http.StatusBadRequest, "slug may only hold letters, digits, '-' and '_'")
+			return
+		}
+
+		link.Path = "/" + body.Slug
+
+		err = a.store.Add(link)
+
+	} else {
+		link, err = a.addRandom(link)
+	}
+
+	if err == ErrExists {
+		writeError(w, http.StatusConflict, "slug '"+body.Slug+"' is already taken")
+		return
+	}
+
+	if err != nil {
+		log.Printf("%v", err)
+		writeError(w, http.StatusInternalServerError, "something went wrong")
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, link)
+
+}
+
+// addRandom adds the link under a random slug, trying again whenever the
+// slug is already taken.
+func (a *Admin) addRandom(link Link) (Link, error) {
+
+	for i := 0; i < slugTries; i++ {
+
+		slug, err := newSlug(slugLength)
+
+		if err != nil {
+			return Link{}, err
+		}
+
+		link.Path = "/" + slug
+
+		err = a.store.Add(link)
+
+		if err != ErrExists {
+			return link, err
+		}
+
+	}
+
+	return Link{}, errors.New("failed to find an unused slug")
+
+}
+
+func newSlug(n int) (string, error) {
+
+	b := make([]byte, n)
+
+	max := big.NewInt(int64(len(base62)))
+
+	for i := range b {
+
+		c, err := rand.Int(rand.Reader, max)
+
+		if err != nil {
+			return "", err
+		}
+
+		b[i] = base62[c.Int64()]
+
+	}
+
+	return string(b), nil
+
+}
+
+func validSlug(slug string) bool {
+
+	for _, c := range slug {
+		if !strings.ContainsRune(base62, c) && c != '-' && c != '_' {
+			return false
+		}
+	}
+
+	return slug != ""
+
+}
+
+func checkURL(s string) error {
+
+	u, err := url.Parse(s)
+
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("url must be an absolute http or https URL")
+	}
+
+	return nil
+
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+
+	w.Header().Set("Content-Type", "application/json")
+
+	w.WriteHeader(status)
+
+	json.NewEncoder(w).Encode(v)
+
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
@@ -0,0 +1,171 @@
+package urlshort
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAdmin(t *testing.T) {
+
+	store := NewMemoryStore(map[string]string{"/a": "https://a.example"})
+
+	admin := NewAdmin(store, WithAdminToken("secret"))
+
+	redirects := StoreHandler(store, notFound)
+
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer secret")
+		admin.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := do("POST", "/_admin/links", `{"url": "https://b.example", "slug": "b"}`)
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("Got status %d (%s), want 201", rec.Code, rec.Body)
+	}
+
+	checkRedirect(t, redirects, "/b", "https://b.example")
+
+	if rec := do("POST", "/_admin/links", `{"url": "https://c.example", "slug": "b"}`); rec.Code != http.StatusConflict {
+		t.Errorf("Got status %d for a taken slug, want 409", rec.Code)
+	}
+
+	rec = do("POST", "/_admin/links", `{"url": "https://c.example"}`)
+
+	var link Link
+
+	json.NewDecoder(rec.Body).Decode(&link)
+
+	if rec.Code != http.StatusCreated || len(link.Path) != slugLength+1 || !validSlug(link.Path[1:]) {
+		t.Fatalf("Got status %d and %+v, want a random slug", rec.Code, link)
+	}
+
+	checkRedirect(t, redirects, link.Path, "https://c.example")
+
+	for _, body := range []string{`{"url": "ftp://c.example"}`, `{"url": "c.example"}`, `{"url": "https://c.example", "slug": "a/b"}`, `[`} {
+		if rec := do("POST", "/_admin/links", body); rec.Code != http.StatusBadRequest {
+			t.Errorf("Got status %d for %s, want 400", rec.Code, body)
+		}
+	}
+
+	var links []Link
+
+	rec = do("GET", "/_admin/links", "")
+
+	json.NewDecoder(rec.Body).Decode(&links)
+
+	if rec.Code != http.StatusOK || len(links) != 3 {
+		t.Errorf("Got status %d and %+v, want 3 links", rec.Code, links)
+	}
+
+	if rec := do("DELETE", "/_admin/links/b", ""); rec.Code != http.StatusNoContent {
+		t.Errorf("Got status %d, want 204", rec.Code)
+	}
+
+	checkRedirect(t, redirects, "/b", "")
+
+	if rec := do("DELETE", "/_admin/links/b", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("Got status %d for a missing slug, want 404", rec.Code)
+	}
+
+	if rec := do("PUT", "/_admin/links", ""); rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Got status %d, want 405", rec.Code)
+	}
+
+	if rec := do("PUT", "/_admin/links/gh/go", `{"url": "https://go.dev"}`); rec.Code != http.StatusOK {
+		t.Errorf("Got status %d (%s) for a path of slugs, want 200", rec.Code, rec.Body)
+	}
+
+	checkRedirect(t, redirects, "/gh/go", "https://go.dev")
+
+	for _, test := range []struct{ path, body string }{
+		{"/_admin/links/", `{"url": "https://d.example"}`},
+		{"/_admin/links/d/", `{"url": "https://d.example"}`},
+		{"/_admin/links/d.txt", `{"url": "https://d.example"}`},
+		{"/_admin/links/d", `{"url": "/e"}`},
+		{"/_admin/links/d", `{"url": "/d"}`},
+		{"/_admin/links/d", `{"url": "ftp://d.example"}`},
+	} {
+		if rec := do("PUT", test.path, test.body); rec.Code != http.StatusBadRequest {
+			t.Errorf("Got status %d for PUT %s %s, want 400", rec.Code, test.path, test.body)
+		}
+	}
+
+}
+
+func TestAdminToken(t *testing.T) {
+
+	store := NewMemoryStore(nil)
+
+	body := `{"url": "https://evil.example", "slug": "x"}`
+
+	for _, test := range []struct {
+		admin  *Admin
+		header string
+		status int
+	}{
+		{NewAdmin(store), "", http.StatusForbidden},
+		{NewAdmin(store), "Bearer ", http.StatusForbidden},
+		{NewAdmin(store, WithAdminToken("secret")), "", http.StatusUnauthorized},
+		{NewAdmin(store, WithAdminToken("secret")), "Bearer wrong", http.StatusUnauthorized},
+		{NewAdmin(store, WithAdminToken("secret")), "secret", http.StatusUnauthorized},
+	} {
+
+		rec := httptest.NewRecorder()
+
+		req := httptest.NewRequest("POST", "/_admin/links", strings.NewReader(body))
+
+		if test.header != "" {
+			req.Header.Set("Authorization", test.header)
+		}
+
+		test.admin.ServeHTTP(rec, req)
+
+		if rec.Code != test.status {
+			t.Errorf("%q: got status %d, want %d", test.header, rec.Code, test.status)
+		}
+
+	}
+
+	if _, ok, _ := store.Lookup("/x"); ok {
+		t.Errorf("Expected no link to have been created without a valid token")
+	}
+
+}
+
+func TestAdminStoreError(t *testing.T) {
+
+	store, err := OpenBoltStore(filepath.Join(t.TempDir(), "links.db"))
+
+	if err != nil {
+		t.Fatalf("OpenBoltStore() returned an error: %s", err)
+	}
+
+	// Every operation on a closed store fails
+
+	store.Close()
+
+	admin := NewAdmin(store, WithAdminToken("secret"))
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/_admin/links", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+
+	admin.ServeHTTP(rec, req)
+
+	var body map[string]string
+
+	json.NewDecoder(rec.Body).Decode(&body)
+
+	if rec.Code != http.StatusInternalServerError || body["error"] == "" {
+		t.Errorf("Got status %d and %v, want 500 with a JSON error", rec.Code, body)
+	}
+
+}
@@ -0,0 +1,256 @@
+package urlshort
+
+import (
+	"log"
+	"net"
+	"net/http"
+	"sort"
+	"time"
+)
+
+// Hit is a single request that was redirected.
+type Hit struct {
+	Path      string    `json:"path"`
+	Time      time.Time `json:"time"`
+	Referer   string    `json:"referer,omitempty"`
+	UserAgent string    `json:"user_agent,omitempty"`
+	IP        string    `json:"ip,omitempty"`
+}
+
+// HitLog is a record of the hits on each path.
+type HitLog interface {
+	// AddHits records a batch of hits
+	AddHits(hits []Hit) error
+	// Hits returns the hits on a path, oldest first
+	Hits(path string) ([]Hit, error)
+}
+
+// Recorder batches hits in the background before adding them to a HitLog,
+// so that recording a hit never waits on storage.
+type Recorder struct {
+	log  HitLog
+	hits chan Hit
+	done chan struct{}
+}
+
+// NewRecorder returns a Recorder that adds hits to the log in batches of up
+// to batchSize, or after interval, whichever comes first. The Recorder must
+// be closed once it is no longer needed.
+func NewRecorder(hitLog HitLog, batchSize int, interval time.Duration) *Recorder {
+
+	r := &Recorder{
+		log:  hitLog,
+		hits: make(chan Hit, batchSize*4),
+		done: make(chan struct{}),
+	}
+
+	go r.run(batchSize, interval)
+
+	return r
+
+}
+
+// Record queues a hit. If the queue is full, the hit is dropped rather than
+// holding up the redirect.
+func (r *Recorder) Record(hit Hit) {
+
+	select {
+	case r.hits <- hit:
+	default:
+		log.Printf("Dropped a hit on %s: the queue is full", hit.Path)
+	}
+
+}
+
+// Close adds any queued hits to the log. Record must not be called after
+// Close.
+func (r *Recorder) Close() {
+
+	close(r.hits)
+
+	<-r.done
+
+}
+
+func (r *Recorder) run(batchSize int, interval time.Duration) {
+
+	defer close(r.done)
+
+	ticker := time.NewTicker(interval)
+
+	defer ticker.Stop()
+
+	batch := make([]Hit, 0, batchSize)
+
+	flush := func() {
+
+		if len(batch) == 0 {
+			return
+		}
+
+		if err := r.log.AddHits(batch); err != nil {
+			log.Printf("Failed to record %d hits: %s", len(batch), err)
+		}
+
+		batch = make([]Hit, 0, batchSize)
+
+	}
+
+	for {
+
+		select {
+		case hit, ok := <-r.hits:
+
+			if !ok {
+				flush()
+				return
+			}
+
+			batch = append(batch, hit)
+
+			if len(batch) >= batchSize {
+				flush()
+			}
+
+		case <-ticker.C:
+			flush()
+		}
+
+	}
+
+}
+
+// TrackHits will return an http.HandlerFunc that passes each request on to
+// h, recording a Hit whenever h responds with a redirect for a link in store.
+// Redirects from anywhere else (such as a file's wildcard entries) aren't
+// recorded, so that hits are only ever kept for paths the store knows about.
+func TrackHits(h http.Handler, store Store, r *Recorder) http.HandlerFunc {
+
+	return func(w http.ResponseWriter, req *http.Request) {
+
+		sw := &statusWriter{ResponseWriter: w}
+
+		h.ServeHTTP(sw, req)
+
+		switch sw.status {
+		case http.StatusMovedPermanently, http.StatusFound, http.StatusSeeOther,
+			http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+		default:
+			return
+		}
+
+		link, ok, err := store.Lookup(req.URL.Path)
+
+		if err != nil {
+			log.Printf("%v", err)
+		}
+
+		if !ok {
+			return
+		}
+
+		ip, _, err := net.SplitHostPort(req.RemoteAddr)
+
+		if err != nil {
+			ip = req.RemoteAddr
+		}
+
+		r.Record(Hit{
+			Path:      link.Path,
+			Time:      time.Now(),
+			Referer:   req.Referer(),
+			UserAgent: req.UserAgent(),
+			IP:        ip,
+		})
+
+	}
+
+}
+
+// statusWriter remembers the status code written to a ResponseWriter.
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+
+	w.status = status
+
+	w.ResponseWriter.WriteHeader(status)
+
+}
+
+// Stats summarizes the hits on a path.
+type Stats struct {
+	Path     string     `json:"path"`
+	Total    int        `json:"total"`
+	Days     []DayCount `json:"days"`
+	Referers []Referer  `json:"referers"`
+}
+
+// DayCount is the number of hits on a single day (in UTC).
+type DayCount struct {
+	Date  string `json:"date"`
+	Count int    `json:"count"`
+}
+
+// Referer is the number of hits that came from a single referer.
+type Referer struct {
+	Referer string `json:"referer"`
+	Count   int    `json:"count"`
+}
+
+// Summarize returns the Stats for the hits on a path, keeping only the top
+// referers (by count).
+func Summarize(path string, hits []Hit, top int) Stats {
+
+	s := Stats{
+		Path:     path,
+		Total:    len(hits),
+		Days:     []DayCount{},
+		Referers: []Referer{},
+	}
+
+	days := map[string]int{}
+	referers := map[string]int{}
+
+	for _, hit := range hits {
+
+		days[hit.Time.UTC().Format("2006-01-02")]++
+
+		if hit.Referer != "" {
+			referers[hit.Referer]++
+		}
+
+	}
+
+	for date, count := range days {
+		s.Days = append(s.Days, DayCount{date, count})
+	}
+
+	sort.Slice(s.Days, func(i, j int) bool {
+		return s.Days[i].Date < s.Days[j].Date
+	})
+
+	for referer, count := range referers {
+		s.Referers = append(s.Referers, Referer{referer, count})
+	}
+
+	sort.Slice(s.Referers, func(i, j int) bool {
+
+		if s.Referers[i].Count != s.Referers[j].Count {
+			return s.Referers[i].Count > s.Referers[j].Count
+		}
+
+		return s.Referers[i].Referer < s.Referers[j].Referer
+
+	})
+
+	if len(s.Referers) > top {
+		s.Referers = s.Referers[:top]
+	}
+
+	return s
+
+}
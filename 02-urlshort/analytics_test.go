@@ -0,0 +1,158 @@
+package urlshort
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func testHitLog(t *testing.T, hits HitLog) {
+
+	day := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	err := hits.AddHits([]Hit{
+		{Path: "/a", Time: day, Referer: "https://x.example"},
+		{Path: "/b", Time: day},
+		{Path: "/a", Time: day.Add(time.Hour), Referer: "https://y.example"},
+	})
+
+	if err != nil {
+		t.Fatalf("AddHits() returned an error: %s", err)
+	}
+
+	hits.AddHits([]Hit{{Path: "/a", Time: day.Add(24 * time.Hour), Referer: "https://y.example"}})
+
+	got, err := hits.Hits("/a")
+
+	if err != nil || len(got) != 3 || !got[0].Time.Equal(day) || got[2].Referer != "https://y.example" {
+		t.Errorf("Got %+v, %v, want the 3 hits on /a in order", got, err)
+	}
+
+	if got, _ := hits.Hits("/c"); len(got) != 0 {
+		t.Errorf("Got %+v, want no hits on /c", got)
+	}
+
+}
+
+// testDeleteHits checks that deleting a link also deletes its hits.
+func testDeleteHits(t *testing.T, s interface {
+	Store
+	HitLog
+}) {
+
+	s.Add(Link{Path: "/a", URL: "https://a.example"})
+
+	s.AddHits([]Hit{{Path: "/a"}})
+
+	if err := s.Delete("/a"); err != nil {
+		t.Fatalf("Delete() returned an error: %s", err)
+	}
+
+	if got, err := s.Hits("/a"); err != nil || len(got) != 0 {
+		t.Errorf("Got %+v, %v, want no hits on a deleted link", got, err)
+	}
+
+}
+
+func TestMemoryHitLog(t *testing.T) {
+	testHitLog(t, NewMemoryStore(nil))
+	testDeleteHits(t, NewMemoryStore(nil))
+}
+
+func TestBoltHitLog(t *testing.T) {
+
+	s, err := OpenBoltStore(filepath.Join(t.TempDir(), "links.db"))
+
+	if err != nil {
+		t.Fatalf("OpenBoltStore() returned an error: %s", err)
+	}
+
+	defer s.Close()
+
+	testHitLog(t, s)
+
+	testDeleteHits(t, s)
+
+}
+
+func TestSummarize(t *testing.T) {
+
+	day := time.Date(2024, 5, 1, 23, 0, 0, 0, time.UTC)
+
+	hits := []Hit{
+		{Time: day, Referer: "https://x.example"},
+		{Time: day.Add(2 * time.Hour), Referer: "https://y.example"},
+		{Time: day.Add(3 * time.Hour), Referer: "https://y.example"},
+		{Time: day.Add(4 * time.Hour)},
+	}
+
+	want := Stats{
+		Path:     "/a",
+		Total:    4,
+		Days:     []DayCount{{"2024-05-01", 1}, {"2024-05-02", 3}},
+		Referers: []Referer{{"https://y.example", 2}},
+	}
+
+	if got := Summarize("/a", hits, 1); !reflect.DeepEqual(got, want) {
+		t.Errorf("Got %+v, want %+v", got, want)
+	}
+
+}
+
+func TestTrackHits(t *testing.T) {
+
+	store := NewMemoryStore(map[string]string{"/a": "https://a.example"})
+
+	recorder := NewRecorder(store, 10, time.Hour)
+
+	files := MapHandler(map[string]string{"/files/*": "https://files.example/*"}, notFound)
+
+	checkRedirect(t, files, "/files/x", "https://files.example/x")
+
+	h := TrackHits(StoreHandler(store, files), store, recorder)
+
+	req := httptest.NewRequest("GET", "/a", nil)
+
+	req.Header.Set("Referer", "https://x.example")
+	req.Header.Set("User-Agent", "test")
+
+	h.ServeHTTP(httptest.NewRecorder(), req)
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/b", nil))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/files/x", nil))
+
+	recorder.Close()
+
+	hits, _ := store.Hits("/a")
+
+	if len(hits) != 1 || hits[0].Referer != "https://x.example" || hits[0].UserAgent != "test" || hits[0].IP != "192.0.2.1" {
+		t.Errorf("Got %+v, want a single hit on /a", hits)
+	}
+
+	if hits, _ := store.Hits("/b"); len(hits) != 0 {
+		t.Errorf("Got %+v, want no hits on a path that wasn't redirected", hits)
+	}
+
+	if hits, _ := store.Hits("/files/x"); len(hits) != 0 {
+		t.Errorf("Got %+v, want no hits on a path that isn't a stored link", hits)
+	}
+
+	rec := httptest.NewRecorder()
+
+	req = httptest.NewRequest("GET", "/_admin/stats/a", nil)
+
+	req.Header.Set("Authorization", "Bearer secret")
+
+	NewAdmin(store, WithHits(store), WithAdminToken("secret")).ServeHTTP(rec, req)
+
+	var stats Stats
+
+	json.NewDecoder(rec.Body).Decode(&stats)
+
+	if stats.Total != 1 || len(stats.Referers) != 1 {
+		t.Errorf("Got %+v, want stats for the hit on /a", stats)
+	}
+
+}
@@ -0,0 +1,272 @@
+package urlshort
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var linksBucket = []byte("links")
+
+var hitsBucket = []byte("hits")
+
+// BoltStore is a Store (and HitLog) that is kept in a Bolt database file,
+// so that links survive restarts.
+type BoltStore struct {
+	db *bolt.DB
+}
+
+// OpenBoltStore opens (or creates) the Bolt database at path. The store
+// must be closed once it is no longer needed.
+func OpenBoltStore(path string) (*BoltStore, error) {
+
+	db, err := bolt.Open(path, 0666, &bolt.Options{Timeout: 1 * time.Second})
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Guarantee that the links and hits buckets exist
+
+	err = db.Update(func(tx *bolt.Tx) error {
+
+		for _, name := range [][]byte{linksBucket, hitsBucket} {
+
+			_, err := tx.CreateBucketIfNotExists(name)
+
+			if err != nil {
+				return fmt.Errorf("called CreateBucketIfNotExists() with name '%s'", name)
+			}
+
+		}
+
+		return nil
+
+	})
+
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &BoltStore{db}, nil
+
+}
+
+// Close releases the database file.
+func (s *BoltStore) Close() error {
+	return s.db.Close()
+}
+
+// Lookup returns the link for a path, if there is one.
+func (s *BoltStore) Lookup(path string) (Link, bool, error) {
+
+	var link Link
+
+	var ok bool
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+
+		v := tx.Bucket(linksBucket).Get([]byte(path))
+
+		if v == nil {
+			return nil
+		}
+
+		ok = true
+
+		return json.Unmarshal(v, &link)
+
+	})
+
+	if err != nil {
+		return Link{}, false, fmt.Errorf("failed to look up link with path '%s': %s", path, err)
+	}
+
+	return link, ok, nil
+
+}
+
+// Put adds a link, replacing any existing link with the same path.
+func (s *BoltStore) Put(link Link) error {
+
+	v, err := json.Marshal(link)
+
+	if err != nil {
+		return err
+	}
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(linksBucket).Put([]byte(link.Path), v)
+	})
+
+}
+
+// Add adds a link, unless there is already a link with the same path.
+func (s *BoltStore) Add(link Link) error {
+
+	v, err := json.Marshal(link)
+
+	if err != nil {
+		return err
+	}
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+
+		b := tx.Bucket(linksBucket)
+
+		if b.Get([]byte(link.Path)) != nil {
+			return ErrExists
+		}
+
+		return b.Put([]byte(link.Path), v)
+
+	})
+
+}
+
+// Delete removes the link for a path, along with its hits.
+func (s *BoltStore) Delete(path string) error {
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+
+		b := tx.Bucket(linksBucket)
+
+		if b.Get([]byte(path)) == nil {
+			return ErrNotFound
+		}
+
+		if err := b.Delete([]byte(path)); err != nil {
+			return err
+		}
+
+		hits := tx.Bucket(hitsBucket)
+
+		if hits.Bucket([]byte(path)) == nil {
+			return nil
+		}
+
+		return hits.DeleteBucket([]byte(path))
+
+	})
+
+}
+
+// List returns every link, ordered by path.
+func (s *BoltStore) List() ([]Link, error) {
+
+	links := []Link{}
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+
+		return tx.Bucket(linksBucket).ForEach(func(k, v []byte) error {
+
+			var link Link
+
+			if err := json.Unmarshal(v, &link); err != nil {
+				return fmt.Errorf("failed to unmarshal link with path '%s': %s", k, err)
+			}
+
+			links = append(links, link)
+
+			return nil
+
+		})
+
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return links, nil
+
+}
+
+// AddHits records a batch of hits. Each path's hits are kept in their own
+// bucket, keyed by sequence number.
+func (s *BoltStore) AddHits(hits []Hit) error {
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+
+		for _, hit := range hits {
+
+			b, err := tx.Bucket(hitsBucket).CreateBucketIfNotExists([]byte(hit.Path))
+
+			if err != nil {
+				return err
+			}
+
+			seq, err := b.NextSequence()
+
+			if err != nil {
+				return err
+			}
+
+			v, err := json.Marshal(hit)
+
+			if err != nil {
+				return err
+			}
+
+			if err := b.Put(itob(seq), v); err != nil {
+				return err
+			}
+
+		}
+
+		return nil
+
+	})
+
+}
+
+// Hits returns the hits on a path, oldest first.
+func (s *BoltStore) Hits(path string) ([]Hit, error) {
+
+	hits := []Hit{}
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+
+		b := tx.Bucket(hitsBucket).Bucket([]byte(path))
+
+		if b == nil {
+			return nil
+		}
+
+		return b.ForEach(func(k, v []byte) error {
+
+			var hit Hit
+
+			if err := json.Unmarshal(v, &hit); err != nil {
+				return fmt.Errorf("failed to unmarshal hit on path '%s': %s", path, err)
+			}
+
+			hits = append(hits, hit)
+
+			return nil
+
+		})
+
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return hits, nil
+
+}
+
+func itob(v uint64) []byte {
+
+	b := make([]byte, 8)
+
+	binary.BigEndian.PutUint64(b, v)
+
+	return b
+
+}
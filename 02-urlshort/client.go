@@ -0,0 +1,173 @@
+package urlshort
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// AdminClient is a Store backed by the admin API of a running server, so
+// that links can be changed while the server holds its own store open.
+type AdminClient struct {
+	base   string
+	token  string
+	client *http.Client
+}
+
+// NewAdminClient returns an AdminClient for the server at baseURL (e.g.
+// "http://localhost:8080"), authenticating with the given admin token.
+func NewAdminClient(baseURL, token string) *AdminClient {
+
+	return &AdminClient{
+		base:   strings.TrimSuffix(baseURL, "/"),
+		token:  token,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+
+}
+
+// Close does nothing; it lets an AdminClient stand in for a BoltStore.
+func (c *AdminClient) Close() error {
+	return nil
+}
+
+// Lookup returns the link for a path, if there is one.
+func (c *AdminClient) Lookup(path string) (Link, bool, error) {
+
+	var link Link
+
+	status, err := c.do(http.MethodGet, "links"+path, nil, &link)
+
+	if status == http.StatusNotFound {
+		return Link{}, false, nil
+	}
+
+	if err != nil {
+		return Link{}, false, err
+	}
+
+	return link, true, nil
+
+}
+
+// Put adds a link, replacing any existing link with the same path.
+func (c *AdminClient) Put(link Link) error {
+
+	_, err := c.do(http.MethodPut, "links"+link.Path, link, nil)
+
+	return err
+
+}
+
+// Add adds a link, unless there is already a link with the same path. The
+// path must be a single slug, as when creating a link through the API.
+func (c *AdminClient) Add(link Link) error {
+
+	body := newLink{
+		URL:     link.URL,
+		Slug:    strings.TrimPrefix(link.Path, "/"),
+		Created: link.Created,
+	}
+
+	status, err := c.do(http.MethodPost, "links", body, nil)
+
+	if status == http.StatusConflict {
+		return ErrExists
+	}
+
+	return err
+
+}
+
+// Delete removes the link for a path.
+func (c *AdminClient) Delete(path string) error {
+
+	status, err := c.do(http.MethodDelete, "links"+path, nil, nil)
+
+	if status == http.StatusNotFound {
+		return ErrNotFound
+	}
+
+	return err
+
+}
+
+// List returns every link, ordered by path.
+func (c *AdminClient) List() ([]Link, error) {
+
+	var links []Link
+
+	if _, err := c.do(http.MethodGet, "links", nil, &links); err != nil {
+		return nil, err
+	}
+
+	return links, nil
+
+}
+
+// do sends a request to the admin API, encoding in as the body and
+// decoding a successful response into out. It returns the response's
+// status along with an error for any status other than 2xx.
+func (c *AdminClient) do(method, path string, in, out interface{}) (int, error) {
+
+	var body io.Reader
+
+	if in != nil {
+
+		data, err := json.Marshal(in)
+
+		if err != nil {
+			return 0, err
+		}
+
+		body = bytes.NewReader(data)
+
+	}
+
+	u := c.base + (&url.URL{Path: AdminPrefix + path}).EscapedPath()
+
+	req, err := http.NewRequest(method, u, body)
+
+	if err != nil {
+		return 0, err
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+
+	if err != nil {
+		return 0, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+
+		var e struct {
+			Error string `json:"error"`
+		}
+
+		json.NewDecoder(resp.Body).Decode(&e)
+
+		if e.Error == "" {
+			e.Error = resp.Status
+		}
+
+		return resp.StatusCode, fmt.Errorf("%s %s: %s", method, u, e.Error)
+
+	}
+
+	if out != nil && resp.StatusCode != http.StatusNoContent {
+		return resp.StatusCode, json.NewDecoder(resp.Body).Decode(out)
+	}
+
+	return resp.StatusCode, nil
+
+}
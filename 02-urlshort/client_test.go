@@ -0,0 +1,41 @@
+package urlshort
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestAdminClient(t *testing.T) {
+
+	store := NewMemoryStore(nil)
+
+	ts := httptest.NewServer(NewAdmin(store, WithAdminToken("secret")))
+
+	defer ts.Close()
+
+	c := NewAdminClient(ts.URL, "secret")
+
+	testStore(t, c)
+
+	// Links keep their creation dates, and paths may hold several segments
+
+	created := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	if err := c.Put(Link{Path: "/docs/go", URL: "https://go.dev", Created: created}); err != nil {
+		t.Fatalf("Put() returned an error: %s", err)
+	}
+
+	if link, ok, err := c.Lookup("/docs/go"); err != nil || !ok || !link.Created.Equal(created) {
+		t.Errorf("Got %+v, %t, %v, want the link with its creation date", link, ok, err)
+	}
+
+	if err := c.Put(Link{Path: "/bad", URL: "ftp://bad.example"}); err == nil {
+		t.Errorf("Expected an error for a non-http URL")
+	}
+
+	if _, err := NewAdminClient(ts.URL, "wrong").List(); err == nil {
+		t.Errorf("Expected an error for the wrong token")
+	}
+
+}
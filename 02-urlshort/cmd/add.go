@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/MichaelZalla/gophercises/urlshort"
+	"github.com/spf13/cobra"
+)
+
+// addCmd represents the add command
+var addCmd = &cobra.Command{
+	Use:   "add <slug> <url>",
+	Short: "Add a link from /slug to a URL",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+
+		force, err := cmd.Flags().GetBool("force")
+
+		if err != nil {
+			return err
+		}
+
+		link, err := urlshort.NewLink(args[0], args[1])
+
+		if err != nil {
+			return err
+		}
+
+		store, err := openStore()
+
+		if err != nil {
+			return err
+		}
+
+		defer store.Close()
+
+		if force {
+			err = store.Put(link)
+		} else {
+			err = store.Add(link)
+		}
+
+		if err == urlshort.ErrExists {
+			return fmt.Errorf("%s is already taken (use --force to replace it)", link.Path)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Added %s -> %s.\n", link.Path, link.URL)
+
+		return nil
+
+	},
+}
+
+func init() {
+
+	rootCmd.AddCommand(addCmd)
+
+	addCmd.Flags().BoolP("force", "f", false, "Replace any existing link with the same slug")
+
+}
@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/MichaelZalla/gophercises/urlshort"
+	"github.com/spf13/cobra"
+)
+
+// exportCmd represents the export command
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Write all of your links to stdout",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+
+		format, err := cmd.Flags().GetString("format")
+
+		if err != nil {
+			return err
+		}
+
+		store, err := openStore()
+
+		if err != nil {
+			return err
+		}
+
+		defer store.Close()
+
+		links, err := store.List()
+
+		if err != nil {
+			return err
+		}
+
+		return urlshort.WriteLinks(os.Stdout, links, format)
+
+	},
+}
+
+func init() {
+
+	rootCmd.AddCommand(exportCmd)
+
+	exportCmd.Flags().String("format", "yaml", "Output format: json, yaml or csv")
+
+}
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/MichaelZalla/gophercises/urlshort"
+	"github.com/spf13/cobra"
+)
+
+// importCmd represents the import command
+var importCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Add the links in a YAML, JSON or CSV file, replacing any with the same path",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+
+		links, err := urlshort.ReadLinks(args[0])
+
+		if err != nil {
+			return err
+		}
+
+		store, err := openStore()
+
+		if err != nil {
+			return err
+		}
+
+		defer store.Close()
+
+		for _, link := range links {
+
+			if err := store.Put(link); err != nil {
+				return err
+			}
+
+		}
+
+		fmt.Printf("Imported %d link(s) from %s.\n", len(links), args[0])
+
+		return nil
+
+	},
+}
+
+func init() {
+
+	rootCmd.AddCommand(importCmd)
+
+}
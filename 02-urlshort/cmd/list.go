@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// listCmd represents the list command
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List all of your links",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+
+		store, err := openStore()
+
+		if err != nil {
+			return err
+		}
+
+		defer store.Close()
+
+		links, err := store.List()
+
+		if err != nil {
+			return err
+		}
+
+		if len(links) == 0 {
+			fmt.Println("You have no links at this time.")
+			return nil
+		}
+
+		fmt.Printf("You have %d link(s):\n", len(links))
+
+		for _, link := range links {
+			fmt.Printf("%s -> %s\n", link.Path, link.URL)
+		}
+
+		return nil
+
+	},
+}
+
+func init() {
+
+	rootCmd.AddCommand(listCmd)
+
+}
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/MichaelZalla/gophercises/urlshort"
+	"github.com/spf13/cobra"
+)
+
+// rmCmd represents the rm command
+var rmCmd = &cobra.Command{
+	Use:   "rm <slug>...",
+	Short: "Remove one or more links",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+
+		store, err := openStore()
+
+		if err != nil {
+			return err
+		}
+
+		defer store.Close()
+
+		for _, slug := range args {
+
+			path := "/" + strings.TrimPrefix(slug, "/")
+
+			err := store.Delete(path)
+
+			if err == urlshort.ErrNotFound {
+				return fmt.Errorf("there is no link with the path %s", path)
+			}
+
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("You have removed the link from %s.\n", path)
+
+		}
+
+		return nil
+
+	},
+}
+
+func init() {
+
+	rootCmd.AddCommand(rmCmd)
+
+}
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/MichaelZalla/gophercises/urlshort"
+	"github.com/spf13/cobra"
+)
+
+// storePath is the Bolt database shared by every command
+var storePath string
+
+// adminToken authenticates requests to the admin API
+var adminToken string
+
+// serverURL is a running server whose store the commands should change
+// through its admin API, rather than opening the store themselves
+var serverURL string
+
+// linkStore is a Store that must be closed once it is no longer needed.
+type linkStore interface {
+	urlshort.Store
+	Close() error
+}
+
+// rootCmd represents the base command when called without any subcommands
+var rootCmd = &cobra.Command{
+	Use:   "urlshort",
+	Short: "urlshort is a URL shortener and a CLI for managing its links.",
+}
+
+// Execute adds all child commands to the root command and sets flags appropriately.
+// This is called by main.main(). It only needs to happen once to the rootCmd.
+func Execute() {
+	if err := rootCmd.Execute(); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
+
+func init() {
+
+	rootCmd.PersistentFlags().StringVar(&storePath, "store", "urlshort.db", "Path to the Bolt database of links")
+
+	rootCmd.PersistentFlags().StringVar(&serverURL, "server", os.Getenv("URLSHORT_SERVER"), "URL of a running server to manage links through, instead of opening the --store (defaults to $URLSHORT_SERVER)")
+
+	rootCmd.PersistentFlags().StringVar(&adminToken, "admin-token", os.Getenv("URLSHORT_ADMIN_TOKEN"), "Token for the admin API (defaults to $URLSHORT_ADMIN_TOKEN)")
+
+}
+
+// openStore returns the store that the commands should change: the admin
+// API of the --server if there is one, or else the --store itself.
+func openStore() (linkStore, error) {
+
+	if serverURL != "" {
+		return urlshort.NewAdminClient(serverURL, adminToken), nil
+	}
+
+	return openDB()
+
+}
+
+// openDB opens the Bolt database named by the --store flag. The database
+// must be closed once it is no longer needed.
+func openDB() (*urlshort.BoltStore, error) {
+
+	db, err := urlshort.OpenBoltStore(storePath)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the store at '%s' (if it is being served, use --server to go through the server): %s", storePath, err)
+	}
+
+	return db, nil
+
+}
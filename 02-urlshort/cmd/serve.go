@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/MichaelZalla/gophercises/urlshort"
+	"github.com/spf13/cobra"
+)
+
+// serveCmd represents the serve command
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve redirects for the links in the store",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+
+		addr, _ := cmd.Flags().GetString("addr")
+		filePath, _ := cmd.Flags().GetString("file")
+		watch, _ := cmd.Flags().GetDuration("watch")
+		strict, _ := cmd.Flags().GetBool("strict")
+		baseURL, _ := cmd.Flags().GetString("base-url")
+
+		var previewOpts []urlshort.PreviewOption
+
+		if baseURL != "" {
+
+			if u, err := url.Parse(baseURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				return fmt.Errorf("invalid --base-url '%s': must be an absolute http or https URL", baseURL)
+			}
+
+			previewOpts = append(previewOpts, urlshort.WithBaseURL(baseURL))
+
+		}
+
+		var opts []urlshort.HandlerOption
+
+		if strict {
+			opts = append(opts, urlshort.Strict())
+		}
+
+		// Links in the file (if there is one) are used for any paths that
+		// aren't in the store
+
+		var handler http.Handler = http.NotFoundHandler()
+
+		if filePath != "" && watch > 0 {
+
+			reloader, err := urlshort.NewReloadingHandler(filePath, handler, opts...)
+
+			if err != nil {
+				return err
+			}
+
+			go reloader.Watch(cmd.Context(), watch)
+
+			handler = reloader
+
+		} else if filePath != "" {
+
+			fileHandler, err := urlshort.FileHandler(filePath, handler, opts...)
+
+			if err != nil {
+				return err
+			}
+
+			handler = fileHandler
+
+		}
+
+		store, err := openDB()
+
+		if err != nil {
+			return err
+		}
+
+		defer store.Close()
+
+		// Record every redirect in the background, in batches
+
+		recorder := urlshort.NewRecorder(store, 100, time.Second)
+
+		defer recorder.Close()
+
+		mux := http.NewServeMux()
+
+		// The admin API is disabled unless it has a token to check requests
+		// against
+
+		if adminToken == "" {
+			fmt.Println("The admin API is disabled (set --admin-token or URLSHORT_ADMIN_TOKEN to enable it)")
+		}
+
+		mux.Handle(urlshort.AdminPrefix, urlshort.NewAdmin(store, urlshort.WithHits(store), urlshort.WithAdminToken(adminToken)))
+
+		// Serve "/slug+" previews and "/slug.png" QR codes for stored links ahead
+		// of redirects
+
+		redirects := urlshort.StoreHandler(store, handler)
+
+		mux.Handle("/", urlshort.TrackHits(urlshort.PreviewHandler(store, store, redirects, previewOpts...), store, recorder))
+
+		server := &http.Server{Addr: addr, Handler: mux}
+
+		// Shut down cleanly on an interrupt, so that queued hits are saved
+
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+
+		defer stop()
+
+		go func() {
+
+			<-ctx.Done()
+
+			shutdown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+
+			defer cancel()
+
+			server.Shutdown(shutdown)
+
+		}()
+
+		fmt.Printf("Starting the server on %s\n", addr)
+
+		if err := server.ListenAndServe(); err != http.ErrServerClosed {
+			return err
+		}
+
+		return nil
+
+	},
+}
+
+func init() {
+
+	rootCmd.AddCommand(serveCmd)
+
+	serveCmd.Flags().String("addr", ":8080", "Address to listen on")
+	serveCmd.Flags().String("file", "", "Path to a YAML, JSON or CSV file of links to use for paths that aren't in the store")
+	serveCmd.Flags().Duration("watch", 0, "How often to check the --file for changes and reload it (0 disables reloading)")
+	serveCmd.Flags().String("base-url", "", "Public URL that short paths are appended to in QR codes (e.g. https://go.example; defaults to the request's host)")
+	serveCmd.Flags().Bool("strict", false, "Refuse to start if the --file has problems, rather than only logging them (a reload with problems is always refused)")
+
+}
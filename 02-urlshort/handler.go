@@ -1,14 +1,86 @@
 package urlshort
 
 import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
 	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
 
 	"gopkg.in/yaml.v2"
 )
 
+// HandlerOption configures how a handler treats problems with its table
+// of links.
+type HandlerOption func(h *handlerOptions)
+
+type handlerOptions struct {
+	strict bool
+	warn   func(err error)
+}
+
+// Strict makes a handler constructor return an error for any problems with
+// its table of links, rather than only warning about them.
+func Strict() HandlerOption {
+	return func(h *handlerOptions) {
+		h.strict = true
+	}
+}
+
+// WithWarn replaces the function used to warn about problems with a table
+// of links when not in strict mode. By default, problems are logged.
+func WithWarn(fn func(err error)) HandlerOption {
+	return func(h *handlerOptions) {
+		h.warn = fn
+	}
+}
+
 type pathURL struct {
-	Path string `yaml:"path"`
-	URL  string `yaml:"url"`
+	Host         string    `yaml:"host,omitempty" json:"host,omitempty"`
+	Path         string    `yaml:"path" json:"path"`
+	URL          string    `yaml:"url" json:"url"`
+	Status       Status    `yaml:"status,omitempty" json:"status,omitempty"`
+	Expires      time.Time `yaml:"expires,omitempty" json:"expires,omitempty"`
+	Gone         bool      `yaml:"gone,omitempty" json:"gone,omitempty"`
+	ForwardQuery bool      `yaml:"forward_query,omitempty" json:"forward_query,omitempty"`
+	Created      time.Time `yaml:"created,omitempty" json:"created,omitempty"`
+}
+
+func newHandlerOptions(opts []HandlerOption) handlerOptions {
+
+	h := handlerOptions{warn: func(err error) { log.Printf("%v", err) }}
+
+	for _, opt := range opts {
+		opt(&h)
+	}
+
+	return h
+
+}
+
+// newHandler validates the table before building a handler for it,
+// according to the options.
+func newHandler(pathUrls []pathURL, fallback http.Handler, opts []HandlerOption) (http.HandlerFunc, error) {
+
+	h := newHandlerOptions(opts)
+
+	if err := validate(pathUrls); err != nil {
+
+		if h.strict {
+			return nil, err
+		}
+
+		h.warn(err)
+
+	}
+
+	return tableHandler(newTable(pathUrls), fallback), nil
+
 }
 
 func getPathURLs(yml []byte) ([]pathURL, error) {
@@ -25,15 +97,86 @@ func getPathURLs(yml []byte) ([]pathURL, error) {
 
 }
 
-func makeMap(pathUrls []pathURL) map[string]string {
+func getPathURLsJSON(data []byte) ([]pathURL, error) {
+
+	var pathUrls []pathURL
+
+	err := json.Unmarshal(data, &pathUrls)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return pathUrls, nil
+
+}
+
+func getPathURLsCSV(data []byte) ([]pathURL, error) {
+
+	r := csv.NewReader(bytes.NewReader(data))
+
+	r.FieldsPerRecord = -1
+
+	records, err := r.ReadAll()
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Skip over a header record, if there is one
+
+	if len(records) > 0 && strings.EqualFold(records[0][0], "path") {
+		records = records[1:]
+	}
+
+	pathUrls := make([]pathURL, len(records))
+
+	for i, record := range records {
+
+		if len(record) != 2 && len(record) != 3 {
+			return nil, fmt.Errorf("record %d: expected a path, a url and an optional creation time", i+1)
+		}
+
+		pathUrls[i] = pathURL{Path: record[0], URL: record[1]}
+
+		if len(record) == 3 && record[2] != "" {
+
+			created, err := time.Parse(time.RFC3339, record[2])
+
+			if err != nil {
+				return nil, fmt.Errorf("record %d: %s", i+1, err)
+			}
+
+			pathUrls[i].Created = created
+
+		}
+
+	}
+
+	return pathUrls, nil
+
+}
+
+// getPathURLsFile reads the file at path, choosing a format based on its
+// extension.
+func getPathURLsFile(path string) ([]pathURL, error) {
 
-	r := make(map[string]string)
+	data, err := ioutil.ReadFile(path)
 
-	for _, pathURL := range pathUrls {
-		r[pathURL.Path] = pathURL.URL
+	if err != nil {
+		return nil, err
 	}
 
-	return r
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		return getPathURLs(data)
+	case ".json":
+		return getPathURLsJSON(data)
+	case ".csv":
+		return getPathURLsCSV(data)
+	default:
+		return nil, fmt.Errorf("unsupported file extension '%s'", ext)
+	}
 
 }
 
@@ -43,21 +186,34 @@ func makeMap(pathUrls []pathURL) map[string]string {
 // that each key in the map points to, in string format).
 // If the path is not provided in the map, then the fallback
 // http.Handler will be called instead.
+//
+// Paths may also be patterns such as "/gh/:user/:repo" or
+// "/docs/*", whose captured segments are substituted into
+// the URL (e.g. "https://github.com/:user/:repo" or
+// "https://pkg.go.dev/*"). Exact paths win over patterns,
+// and more specific patterns win over less specific ones.
+//
+// Paths may be prefixed with a host (e.g. "go.example/x") to
+// only redirect requests for that host; unprefixed paths
+// apply to any host.
 func MapHandler(pathsToUrls map[string]string, fallback http.Handler) http.HandlerFunc {
 
-	return func(w http.ResponseWriter, r *http.Request) {
+	pathUrls := make([]pathURL, 0, len(pathsToUrls))
 
-		oldPath := r.URL.Path
+	for path, url := range pathsToUrls {
 
-		if newPath, ok := pathsToUrls[oldPath]; ok {
-			http.Redirect(w, r, newPath, http.StatusFound)
-			return
+		var host string
+
+		if i := strings.Index(path, "/"); i > 0 {
+			host, path = path[:i], path[i:]
 		}
 
-		fallback.ServeHTTP(w, r)
+		pathUrls = append(pathUrls, pathURL{Host: host, Path: path, URL: url})
 
 	}
 
+	return tableHandler(newTable(pathUrls), fallback)
+
 }
 
 // YAMLHandler will parse the provided YAML and then return
@@ -71,12 +227,27 @@ func MapHandler(pathsToUrls map[string]string, fallback http.Handler) http.Handl
 //     - path: /some-path
 //       url: https://www.some-url.com/demo
 //
-// The only errors that can be returned all related to having
-// invalid YAML data.
+// Each entry may also set:
+//
+//     status: 301           # the redirect status (301, 302, 307 or 308)
+//     expires: 2030-01-01   # when to stop redirecting
+//     gone: true            # respond 410 Gone once expired, rather
+//                           # than calling the fallback
+//     forward_query: true   # add the request's query parameters
+//                           # to the URL
+//     host: go.example      # only redirect requests for this host
+//
+// Entries without a host (or with the wildcard host "*")
+// apply to requests for any host that has no entry of its
+// own for the path.
+//
+// The table is validated once it has been parsed (see
+// ValidationError). By default, problems are only logged;
+// pass Strict() to return them as an error instead.
 //
 // See MapHandler to create a similar http.HandlerFunc via
 // a mapping of paths to urls.
-func YAMLHandler(yml []byte, fallback http.Handler) (http.HandlerFunc, error) {
+func YAMLHandler(yml []byte, fallback http.Handler, opts ...HandlerOption) (http.HandlerFunc, error) {
 
 	pathUrls, err := getPathURLs(yml)
 
@@ -84,8 +255,53 @@ func YAMLHandler(yml []byte, fallback http.Handler) (http.HandlerFunc, error) {
 		return nil, err
 	}
 
-	pathsToUrls := makeMap(pathUrls)
+	return newHandler(pathUrls, fallback, opts)
+
+}
+
+// JSONHandler will parse the provided JSON and then return
+// an http.HandlerFunc (which also implements http.Handler)
+// that will attempt to map any paths to their corresponding
+// URL. If the path is not provided in the JSON, then the
+// fallback http.Handler will be called instead.
+//
+// JSON is expected to be in the format:
+//
+//     [
+//       { "path": "/some-path", "url": "https://www.some-url.com/demo" }
+//     ]
+//
+// See YAMLHandler for the equivalent handler for YAML data,
+// including the fields each entry may set and how the table
+// is validated.
+func JSONHandler(data []byte, fallback http.Handler, opts ...HandlerOption) (http.HandlerFunc, error) {
+
+	pathUrls, err := getPathURLsJSON(data)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return newHandler(pathUrls, fallback, opts)
+
+}
+
+// FileHandler will read the file at path and then return an
+// http.HandlerFunc that will attempt to map any paths to their
+// corresponding URL. The format of the file is chosen by its
+// extension: .yaml (or .yml) files are read as by YAMLHandler,
+// .json files as by JSONHandler, and .csv files are expected
+// to hold a path and a URL on each line (with an optional
+// RFC 3339 creation time), optionally beneath a "path,url"
+// header. The table is validated as by YAMLHandler.
+func FileHandler(path string, fallback http.Handler, opts ...HandlerOption) (http.HandlerFunc, error) {
+
+	pathUrls, err := getPathURLsFile(path)
+
+	if err != nil {
+		return nil, err
+	}
 
-	return MapHandler(pathsToUrls, fallback), nil
+	return newHandler(pathUrls, fallback, opts)
 
 }
@@ -0,0 +1,91 @@
+package urlshort
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+)
+
+func checkRedirect(t *testing.T, h http.Handler, path, url string) {
+
+	rec := httptest.NewRecorder()
+
+	h.ServeHTTP(rec, httptest.NewRequest("GET", path, nil))
+
+	if url == "" {
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("%s: got status %d, want 404", path, rec.Code)
+		}
+		return
+	}
+
+	if got := rec.Header().Get("Location"); got != url {
+		t.Errorf("%s: got Location %q, want %q", path, got, url)
+	}
+
+}
+
+func TestJSONHandler(t *testing.T) {
+
+	h, err := JSONHandler([]byte(`[{"path": "/a", "url": "https://a.example"}]`), notFound)
+
+	if err != nil {
+		t.Fatalf("JSONHandler() returned an error: %s", err)
+	}
+
+	checkRedirect(t, h, "/a", "https://a.example")
+	checkRedirect(t, h, "/b", "")
+
+	if _, err := JSONHandler([]byte(`{"path": "/a"}`), notFound); err == nil {
+		t.Errorf("Expected an error for a JSON object")
+	}
+
+}
+
+func TestFileHandler(t *testing.T) {
+
+	dir := t.TempDir()
+
+	files := map[string]string{
+		"links.yaml": "- path: /a\n  url: https://a.example\n",
+		"links.json": `[{"path": "/a", "url": "https://a.example"}]`,
+		"links.csv":  "path,url\n/a,https://a.example\n",
+	}
+
+	for name, data := range files {
+
+		path := filepath.Join(dir, name)
+
+		ioutil.WriteFile(path, []byte(data), 0644)
+
+		h, err := FileHandler(path, notFound)
+
+		if err != nil {
+			t.Errorf("%s: FileHandler() returned an error: %s", name, err)
+			continue
+		}
+
+		checkRedirect(t, h, "/a", "https://a.example")
+		checkRedirect(t, h, "/b", "")
+
+	}
+
+	path := filepath.Join(dir, "links.txt")
+
+	ioutil.WriteFile(path, []byte("/a https://a.example"), 0644)
+
+	if _, err := FileHandler(path, notFound); err == nil {
+		t.Errorf("Expected an error for an unknown extension")
+	}
+
+	path = filepath.Join(dir, "bad.csv")
+
+	ioutil.WriteFile(path, []byte("/a,https://a.example,extra\n"), 0644)
+
+	if _, err := FileHandler(path, notFound); err == nil {
+		t.Errorf("Expected an error for a record with too many fields")
+	}
+
+}
@@ -0,0 +1,91 @@
+package urlshort
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHostRouting(t *testing.T) {
+
+	yml := `
+- host: go.example
+  path: /x
+  url: https://go.example.org/x
+- host: docs.example
+  path: /x
+  url: https://docs.example.org/x
+- host: "*"
+  path: /x
+  url: https://default.example/x
+- path: /y
+  url: https://default.example/y
+- host: DOCS.example
+  path: /p/*
+  url: https://docs.example.org/*
+`
+
+	h, err := YAMLHandler([]byte(yml), notFound, Strict())
+
+	if err != nil {
+		t.Fatalf("YAMLHandler() returned an error: %s", err)
+	}
+
+	tests := []struct {
+		host string
+		path string
+		url  string
+	}{
+		{"go.example", "/x", "https://go.example.org/x"},
+		{"go.example:8080", "/x", "https://go.example.org/x"},
+		{"docs.example", "/x", "https://docs.example.org/x"},
+		{"Docs.Example:443", "/p/fmt", "https://docs.example.org/fmt"},
+		{"go.example", "/p/fmt", ""},
+		{"other.example", "/x", "https://default.example/x"},
+		{"go.example", "/y", "https://default.example/y"},
+		{"[::1]:8080", "/y", "https://default.example/y"},
+	}
+
+	for _, test := range tests {
+
+		rec := httptest.NewRecorder()
+
+		req := httptest.NewRequest("GET", test.path, nil)
+
+		req.Host = test.host
+
+		h.ServeHTTP(rec, req)
+
+		if got := rec.Header().Get("Location"); got != test.url {
+			t.Errorf("%s%s: got %q, want %q", test.host, test.path, got, test.url)
+		}
+
+	}
+
+	if _, err := YAMLHandler([]byte("- {host: a.example, path: /x, url: https://a.example}\n- {host: b.example, path: /x, url: https://b.example}\n- {host: A.example, path: /x, url: https://c.example}\n"), notFound, Strict()); err == nil {
+		t.Errorf("Expected an error for a duplicate path on the same host")
+	}
+
+}
+
+func TestMapHandlerHosts(t *testing.T) {
+
+	h := MapHandler(map[string]string{
+		"go.example/x": "https://go.example.org/x",
+		"/x":           "https://default.example/x",
+	}, notFound)
+
+	req := httptest.NewRequest("GET", "/x", nil)
+
+	req.Host = "go.example:80"
+
+	rec := httptest.NewRecorder()
+
+	h.ServeHTTP(rec, req)
+
+	if got := rec.Header().Get("Location"); got != "https://go.example.org/x" {
+		t.Errorf("Got %q, want the go.example link", got)
+	}
+
+	checkRedirect(t, h, "/x", "https://default.example/x")
+
+}
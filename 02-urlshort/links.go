@@ -0,0 +1,146 @@
+package urlshort
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+// NewLink returns a link from the path "/slug" to an absolute http(s) URL,
+// created now.
+func NewLink(slug, rawURL string) (Link, error) {
+
+	if !validSlug(slug) {
+		return Link{}, fmt.Errorf("invalid slug '%s': may only hold letters, digits, '-' and '_'", slug)
+	}
+
+	if err := checkURL(rawURL); err != nil {
+		return Link{}, err
+	}
+
+	return Link{Path: "/" + slug, URL: rawURL, Created: time.Now()}, nil
+
+}
+
+// checkLink reports whether a link could have been made by NewLink, but
+// allowing a path of several slugs (e.g. "/gh/go").
+func checkLink(link Link) error {
+
+	if !strings.HasPrefix(link.Path, "/") {
+		return fmt.Errorf("invalid path '%s': must begin with '/'", link.Path)
+	}
+
+	for _, slug := range strings.Split(link.Path[1:], "/") {
+		if !validSlug(slug) {
+			return fmt.Errorf("invalid path '%s': each segment may only hold letters, digits, '-' and '_'", link.Path)
+		}
+	}
+
+	return checkURL(link.URL)
+
+}
+
+// ReadLinks reads the links in a YAML, JSON or CSV file, chosen by its
+// extension as in FileHandler. Since a Store only holds exact paths and
+// their URLs, the file must pass validation and hold only paths of slugs
+// that redirect to absolute http or https URLs, with no host-scoped entries
+// or entries that set a status, expiry, gone or forward_query. Links without a creation time are created now.
+func ReadLinks(path string) ([]Link, error) {
+
+	pathUrls, err := getPathURLsFile(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validate(pathUrls); err != nil {
+		return nil, err
+	}
+
+	links := make([]Link, len(pathUrls))
+
+	now := time.Now()
+
+	for i, pu := range pathUrls {
+
+		if isPattern(pu.Path) || pu.Host != "" {
+			return nil, fmt.Errorf("entry %d (%s): only exact paths without a host can be stored", i+1, pu.key())
+		}
+
+		if pu.Status != 0 || !pu.Expires.IsZero() || pu.Gone || pu.ForwardQuery {
+			return nil, fmt.Errorf("entry %d (%s): status, expires, gone and forward_query can't be stored", i+1, pu.key())
+		}
+
+		links[i] = Link{Path: pu.Path, URL: pu.URL, Created: pu.Created}
+
+		if err := checkLink(links[i]); err != nil {
+			return nil, fmt.Errorf("entry %d (%s): %s", i+1, pu.key(), err)
+		}
+
+		if links[i].Created.IsZero() {
+			links[i].Created = now
+		}
+
+	}
+
+	return links, nil
+
+}
+
+// WriteLinks writes links to w in the given format: "json", "yaml" or
+// "csv". The output can be read back with ReadLinks.
+func WriteLinks(w io.Writer, links []Link, format string) error {
+
+	switch format {
+	case "json":
+
+		enc := json.NewEncoder(w)
+
+		enc.SetIndent("", "  ")
+
+		return enc.Encode(links)
+
+	case "yaml":
+
+		data, err := yaml.Marshal(links)
+
+		if err != nil {
+			return err
+		}
+
+		_, err = w.Write(data)
+
+		return err
+
+	case "csv":
+
+		cw := csv.NewWriter(w)
+
+		cw.Write([]string{"path", "url", "created"})
+
+		for _, link := range links {
+
+			var created string
+
+			if !link.Created.IsZero() {
+				created = link.Created.Format(time.RFC3339Nano)
+			}
+
+			cw.Write([]string{link.Path, link.URL, created})
+
+		}
+
+		cw.Flush()
+
+		return cw.Error()
+
+	default:
+		return fmt.Errorf("unsupported format '%s'", format)
+	}
+
+}
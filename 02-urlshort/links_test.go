@@ -0,0 +1,112 @@
+package urlshort
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWriteAndReadLinks(t *testing.T) {
+
+	created := time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC)
+
+	links := []Link{
+		{Path: "/a", URL: "https://a.example", Created: created},
+		{Path: "/b", URL: "https://b.example/?x=1,2"},
+	}
+
+	dir := t.TempDir()
+
+	for _, format := range []string{"json", "yaml", "csv"} {
+
+		path := filepath.Join(dir, "links."+format)
+
+		f, err := os.Create(path)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := WriteLinks(f, links, format); err != nil {
+			t.Errorf("%s: WriteLinks() returned an error: %s", format, err)
+		}
+
+		f.Close()
+
+		got, err := ReadLinks(path)
+
+		if err != nil {
+			t.Errorf("%s: ReadLinks() returned an error: %s", format, err)
+			continue
+		}
+
+		if len(got) != len(links) {
+			t.Errorf("%s: got %+v, want %+v", format, got, links)
+			continue
+		}
+
+		for i := range links {
+			if got[i].Path != links[i].Path || got[i].URL != links[i].URL {
+				t.Errorf("%s: got %+v, want %+v", format, got[i], links[i])
+			}
+		}
+
+		if !got[0].Created.Equal(created) || got[1].Created.IsZero() {
+			t.Errorf("%s: got %+v, want the creation date kept, or else now", format, got)
+		}
+
+	}
+
+	if err := WriteLinks(os.Stdout, links, "xml"); err == nil {
+		t.Errorf("Expected an error for an unsupported format")
+	}
+
+	path := filepath.Join(dir, "patterns.yaml")
+
+	ioutil.WriteFile(path, []byte("- path: /gh/:user\n  url: https://github.com/:user\n"), 0644)
+
+	if _, err := ReadLinks(path); err == nil {
+		t.Errorf("Expected an error for a pattern")
+	}
+
+	for _, field := range []string{"status: 301", "expires: 2030-01-01", "gone: true", "forward_query: true"} {
+
+		ioutil.WriteFile(path, []byte("- path: /a\n  url: https://a.example\n  "+field+"\n"), 0644)
+
+		if _, err := ReadLinks(path); err == nil {
+			t.Errorf("Expected an error for an entry with %q", field)
+		}
+
+	}
+
+	for _, entry := range []string{"- path: /a\n  url: /b\n", "- path: /a.txt\n  url: https://a.example\n"} {
+
+		ioutil.WriteFile(path, []byte(entry), 0644)
+
+		if _, err := ReadLinks(path); err == nil {
+			t.Errorf("Expected an error for %q, which NewLink couldn't have made", entry)
+		}
+
+	}
+
+}
+
+func TestNewLink(t *testing.T) {
+
+	link, err := NewLink("go", "https://go.dev")
+
+	if err != nil || link.Path != "/go" || link.URL != "https://go.dev" || link.Created.IsZero() {
+		t.Errorf("Got %+v, %v, want a link from /go", link, err)
+	}
+
+	if _, err := NewLink("a/b", "https://go.dev"); err == nil {
+		t.Errorf("Expected an error for an invalid slug")
+	}
+
+	if _, err := NewLink("go", "go.dev"); err == nil {
+		t.Errorf("Expected an error for a relative URL")
+	}
+
+}
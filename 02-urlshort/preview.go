@@ -0,0 +1,168 @@
+package urlshort
+
+import (
+	"html/template"
+	"log"
+	"net/http"
+	"strings"
+
+	"rsc.io/qr"
+)
+
+var previewTemplate = template.Must(template.New("preview").Parse(`
+	<!DOCTYPE html>
+	<html lang="en">
+	<head>
+		<meta charset="UTF-8">
+		<meta name="viewport" content="width=device-width, initial-scale=1.0">
+		<title>{{ .Link.Path }}</title>
+	</head>
+	<body>
+		<section class="page">
+			<h1>{{ .Link.Path }}</h1>
+			<p>This link goes to <a href="{{ .Link.URL }}">{{ .Link.URL }}</a>.</p>
+			{{ if not .Link.Created.IsZero }}<p>Created on {{ .Link.Created.Format "January 2, 2006" }}.</p>{{ end }}
+			{{ if .Counted }}<p>Followed {{ .Hits }} time(s).</p>{{ end }}
+			<img src="{{ .Link.Path }}.png" alt="QR code for {{ .Link.Path }}">
+		</section>
+	</body>
+	</html>`))
+
+// PreviewOption configures a PreviewHandler.
+type PreviewOption func(p *previewOptions)
+
+type previewOptions struct {
+	baseURL string
+}
+
+// WithBaseURL sets the URL (e.g. "https://go.example") that short paths are
+// appended to in QR codes. Without it, the URL is built from the request,
+// which a client or a TLS-terminating proxy may not report faithfully.
+func WithBaseURL(baseURL string) PreviewOption {
+	return func(p *previewOptions) {
+		p.baseURL = strings.TrimSuffix(baseURL, "/")
+	}
+}
+
+// PreviewHandler will return an http.HandlerFunc that, for any link in the
+// store, serves "/slug+" as an HTML page previewing the link (with its hit
+// count, if hits is not nil) and "/slug.png" as a QR code for the short URL.
+// Every other request, including one for a link whose own path ends in "+"
+// or ".png", is passed on to the fallback http.Handler. Only stored links
+// are previewed: entries in a redirect file, which may be patterns or tied
+// to a host, have no single short URL to show, so "/path+" and "/path.png"
+// for them go to the fallback like any other path.
+func PreviewHandler(store Store, hits HitLog, fallback http.Handler, opts ...PreviewOption) http.HandlerFunc {
+
+	var p previewOptions
+
+	for _, opt := range opts {
+		opt(&p)
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		path := r.URL.Path
+
+		var preview, code bool
+
+		switch {
+		case strings.HasSuffix(path, "+"):
+			path, preview = strings.TrimSuffix(path, "+"), true
+		case strings.HasSuffix(path, ".png"):
+			path, code = strings.TrimSuffix(path, ".png"), true
+		}
+
+		if !preview && !code {
+			fallback.ServeHTTP(w, r)
+			return
+		}
+
+		// A link's own path wins over a preview of another link
+
+		if _, ok, err := store.Lookup(r.URL.Path); err == nil && ok {
+			fallback.ServeHTTP(w, r)
+			return
+		}
+
+		link, ok, err := store.Lookup(path)
+
+		if err != nil {
+			log.Printf("%v", err)
+			http.Error(w, "Something went wrong!", http.StatusInternalServerError)
+			return
+		}
+
+		if !ok {
+			fallback.ServeHTTP(w, r)
+			return
+		}
+
+		if code {
+			servePNG(w, p.shortURL(r, link))
+			return
+		}
+
+		data := struct {
+			Link    Link
+			Counted bool
+			Hits    int
+		}{Link: link}
+
+		if hits != nil {
+
+			linkHits, err := hits.Hits(link.Path)
+
+			if err != nil {
+				log.Printf("%v", err)
+				http.Error(w, "Something went wrong!", http.StatusInternalServerError)
+				return
+			}
+
+			data.Counted, data.Hits = true, len(linkHits)
+
+		}
+
+		if err := previewTemplate.Execute(w, data); err != nil {
+			log.Printf("%v", err)
+			http.Error(w, "Something went wrong!", http.StatusInternalServerError)
+		}
+
+	}
+
+}
+
+// shortURL returns the full short URL of a link, built from the base URL if
+// there is one, or else from the request.
+func (p previewOptions) shortURL(r *http.Request, link Link) string {
+
+	if p.baseURL != "" {
+		return p.baseURL + link.Path
+	}
+
+	scheme := "http"
+
+	if r.TLS != nil {
+		scheme = "https"
+	}
+
+	return scheme + "://" + r.Host + link.Path
+
+}
+
+// servePNG writes a QR code for a URL.
+func servePNG(w http.ResponseWriter, u string) {
+
+	code, err := qr.Encode(u, qr.M)
+
+	if err != nil {
+		log.Printf("%v", err)
+		http.Error(w, "Something went wrong!", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/png")
+
+	w.Write(code.PNG())
+
+}
@@ -0,0 +1,110 @@
+package urlshort
+
+import (
+	"bytes"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"rsc.io/qr"
+)
+
+func TestPreviewHandler(t *testing.T) {
+
+	store := NewMemoryStore(map[string]string{"/x.png": "https://image.example"})
+
+	store.Put(Link{Path: "/go", URL: "https://go.dev/?a=1&b=2", Created: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)})
+
+	store.AddHits([]Hit{{Path: "/go"}, {Path: "/go"}})
+
+	h := PreviewHandler(store, store, StoreHandler(store, notFound))
+
+	get := func(path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest("GET", "http://go.example"+path, nil))
+		return rec
+	}
+
+	rec := get("/go+")
+
+	body := rec.Body.String()
+
+	for _, want := range []string{`href="https://go.dev/?a=1&amp;b=2"`, "May 1, 2024", "Followed 2 time(s)", `src="/go.png"`} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Expected the preview to contain %q:\n%s", want, body)
+		}
+	}
+
+	if rec.Code != 200 || rec.Header().Get("Location") != "" {
+		t.Errorf("Got status %d, want a page rather than a redirect", rec.Code)
+	}
+
+	rec = get("/go.png")
+
+	if rec.Header().Get("Content-Type") != "image/png" {
+		t.Fatalf("Got Content-Type %q, want image/png", rec.Header().Get("Content-Type"))
+	}
+
+	if _, err := png.Decode(rec.Body); err != nil {
+		t.Errorf("Failed to decode the QR code: %s", err)
+	}
+
+	checkRedirect(t, h, "/go", "https://go.dev/?a=1&b=2")
+	checkRedirect(t, h, "/x.png", "https://image.example")
+	checkRedirect(t, h, "/nope+", "")
+	checkRedirect(t, h, "/nope.png", "")
+
+}
+
+func TestPreviewBaseURL(t *testing.T) {
+
+	store := NewMemoryStore(map[string]string{"/go": "https://go.dev"})
+
+	for _, test := range []struct {
+		opts []PreviewOption
+		want string
+	}{
+		{nil, "http://evil.example/go"},
+		{[]PreviewOption{WithBaseURL("https://go.example/")}, "https://go.example/go"},
+	} {
+
+		rec := httptest.NewRecorder()
+
+		PreviewHandler(store, nil, notFound, test.opts...).ServeHTTP(rec, httptest.NewRequest("GET", "http://evil.example/go.png", nil))
+
+		code, _ := qr.Encode(test.want, qr.M)
+
+		if !bytes.Equal(rec.Body.Bytes(), code.PNG()) {
+			t.Errorf("Expected a QR code for %s", test.want)
+		}
+
+	}
+
+}
+
+func TestPreviewStoredLinksOnly(t *testing.T) {
+
+	store := NewMemoryStore(nil)
+
+	files := MapHandler(map[string]string{"/file": "https://file.example"}, notFound)
+
+	h := PreviewHandler(store, store, StoreHandler(store, files))
+
+	checkRedirect(t, h, "/file", "https://file.example")
+
+	for _, path := range []string{"/file+", "/file.png"} {
+
+		rec := httptest.NewRecorder()
+
+		h.ServeHTTP(rec, httptest.NewRequest("GET", path, nil))
+
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("Got status %d for %s, want a file entry to have no preview", rec.Code, path)
+		}
+
+	}
+
+}
@@ -0,0 +1,98 @@
+package urlshort
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Status is the HTTP status code used for a redirect. The zero value means
+// http.StatusFound.
+type Status int
+
+// UnmarshalYAML accepts only the status codes of redirects that keep the
+// destination: 301, 302, 307 and 308.
+func (s *Status) UnmarshalYAML(unmarshal func(interface{}) error) error {
+
+	var code int
+
+	if err := unmarshal(&code); err != nil {
+		return err
+	}
+
+	return s.set(code)
+
+}
+
+// UnmarshalJSON accepts the same status codes as UnmarshalYAML.
+func (s *Status) UnmarshalJSON(data []byte) error {
+
+	var code int
+
+	if err := json.Unmarshal(data, &code); err != nil {
+		return err
+	}
+
+	return s.set(code)
+
+}
+
+func (s *Status) set(code int) error {
+
+	switch code {
+	case http.StatusMovedPermanently, http.StatusFound,
+		http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+		*s = Status(code)
+		return nil
+	default:
+		return fmt.Errorf("unsupported redirect status %d", code)
+	}
+
+}
+
+func (s Status) code() int {
+
+	if s == 0 {
+		return http.StatusFound
+	}
+
+	return int(s)
+
+}
+
+// expired reports whether the entry has an expiry time that has passed.
+func (pu pathURL) expired(now time.Time) bool {
+	return !pu.Expires.IsZero() && !now.Before(pu.Expires)
+}
+
+// redirect redirects the request to the entry's URL, forwarding the
+// request's query parameters if the entry asks for it.
+func (pu pathURL) redirect(w http.ResponseWriter, r *http.Request) {
+
+	dest := pu.URL
+
+	if pu.ForwardQuery && r.URL.RawQuery != "" {
+
+		u, err := url.Parse(dest)
+
+		if err == nil {
+
+			q := u.Query()
+
+			for k, vs := range r.URL.Query() {
+				q[k] = append(q[k], vs...)
+			}
+
+			u.RawQuery = q.Encode()
+
+			dest = u.String()
+
+		}
+
+	}
+
+	http.Redirect(w, r, dest, pu.Status.code())
+
+}
@@ -0,0 +1,85 @@
+package urlshort
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestYAMLHandlerEntryOptions(t *testing.T) {
+
+	yml := `
+- path: /moved
+  url: https://moved.example
+  status: 301
+- path: /temporary
+  url: https://temporary.example
+  status: 307
+- path: /expired
+  url: https://expired.example
+  expires: 2000-01-01
+- path: /gone
+  url: https://gone.example
+  expires: 2000-01-01T12:00:00Z
+  gone: true
+- path: /later
+  url: https://later.example
+  expires: 2999-01-01
+- path: /search
+  url: https://search.example/?source=short
+  forward_query: true
+- path: /docs/*
+  url: https://pkg.go.dev/*
+  forward_query: true
+`
+
+	h, err := YAMLHandler([]byte(yml), notFound)
+
+	if err != nil {
+		t.Fatalf("YAMLHandler() returned an error: %s", err)
+	}
+
+	tests := []struct {
+		path   string
+		status int
+		url    string
+	}{
+		{"/moved", http.StatusMovedPermanently, "https://moved.example"},
+		{"/temporary", http.StatusTemporaryRedirect, "https://temporary.example"},
+		{"/expired", http.StatusNotFound, ""},
+		{"/gone", http.StatusGone, ""},
+		{"/later", http.StatusFound, "https://later.example"},
+		{"/search?q=go&source=x", http.StatusFound, "https://search.example/?q=go&source=short&source=x"},
+		{"/search", http.StatusFound, "https://search.example/?source=short"},
+		{"/docs/fmt?tab=doc", http.StatusFound, "https://pkg.go.dev/fmt?tab=doc"},
+	}
+
+	for _, test := range tests {
+
+		rec := httptest.NewRecorder()
+
+		h.ServeHTTP(rec, httptest.NewRequest("GET", test.path, nil))
+
+		if rec.Code != test.status || rec.Header().Get("Location") != test.url {
+			t.Errorf("%s: got %d %q, want %d %q", test.path, rec.Code, rec.Header().Get("Location"), test.status, test.url)
+		}
+
+	}
+
+}
+
+func TestUnsupportedStatus(t *testing.T) {
+
+	if _, err := YAMLHandler([]byte("- path: /a\n  url: https://a.example\n  status: 303\n"), notFound); err == nil {
+		t.Errorf("Expected an error for a 303 status in YAML")
+	}
+
+	if _, err := JSONHandler([]byte(`[{"path": "/a", "url": "https://a.example", "status": 200}]`), notFound); err == nil {
+		t.Errorf("Expected an error for a 200 status in JSON")
+	}
+
+	if _, err := JSONHandler([]byte(`[{"path": "/a", "url": "https://a.example", "status": 308}]`), notFound); err != nil {
+		t.Errorf("JSONHandler() returned an error for a 308 status: %s", err)
+	}
+
+}
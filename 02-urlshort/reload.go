@@ -0,0 +1,154 @@
+package urlshort
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// ReloadingHandler redirects paths using the table in a YAML, JSON or CSV
+// file, re-reading the file whenever its modification time changes.
+type ReloadingHandler struct {
+	path     string
+	fallback http.Handler
+	opts     handlerOptions
+
+	mu      sync.RWMutex
+	table   *table
+	modTime time.Time
+	size    int64
+}
+
+// NewReloadingHandler loads the table at path, chosen by extension as in
+// FileHandler. Unlike later reloads, a failure here is returned as an error.
+// The options treat problems with this first table as in YAMLHandler; a
+// reloaded table that fails validation is always rejected.
+func NewReloadingHandler(path string, fallback http.Handler, opts ...HandlerOption) (*ReloadingHandler, error) {
+
+	h := &ReloadingHandler{
+		path:     path,
+		fallback: fallback,
+		opts:     newHandlerOptions(opts),
+	}
+
+	if _, err := h.Reload(); err != nil {
+		return nil, err
+	}
+
+	return h, nil
+
+}
+
+func (h *ReloadingHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+
+	h.mu.RLock()
+
+	t := h.table
+
+	h.mu.RUnlock()
+
+	tableHandler(t, h.fallback).ServeHTTP(w, r)
+
+}
+
+// Reload re-reads the file if it has changed since it was last loaded,
+// reporting whether a new table was swapped in. If the file can't be read,
+// holds no entries or fails validation, the previous table is kept, and the
+// file isn't read again until it changes once more.
+func (h *ReloadingHandler) Reload() (bool, error) {
+
+	info, err := os.Stat(h.path)
+
+	if err != nil {
+		return false, err
+	}
+
+	h.mu.RLock()
+
+	initial := h.table == nil
+
+	unchanged := !initial && info.ModTime().Equal(h.modTime) && info.Size() == h.size
+
+	h.mu.RUnlock()
+
+	if unchanged {
+		return false, nil
+	}
+
+	pathUrls, err := getPathURLsFile(h.path)
+
+	if err == nil && len(pathUrls) == 0 {
+		err = errors.New("no entries")
+	}
+
+	var problems error
+
+	if err == nil {
+
+		// Only the first table may be served despite its problems; after
+		// that, a bad edit shouldn't replace a table that's working
+
+		if problems = validate(pathUrls); problems != nil && (h.opts.strict || !initial) {
+			err, problems = problems, nil
+		}
+
+	}
+
+	// Remember this version of the file even if it was rejected, so that
+	// the same problems aren't reported again on every poll
+
+	h.mu.Lock()
+
+	if err == nil {
+		h.table = newTable(pathUrls)
+	}
+
+	h.modTime = info.ModTime()
+	h.size = info.Size()
+
+	h.mu.Unlock()
+
+	if err != nil {
+		return false, fmt.Errorf("%s: %s", h.path, err)
+	}
+
+	if problems != nil {
+		h.opts.warn(fmt.Errorf("%s: %s", h.path, problems))
+	}
+
+	return true, nil
+
+}
+
+// Watch polls the file every interval until ctx is done, reloading it when
+// it changes and logging any reload that fails.
+func (h *ReloadingHandler) Watch(ctx context.Context, interval time.Duration) {
+
+	ticker := time.NewTicker(interval)
+
+	defer ticker.Stop()
+
+	for {
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		reloaded, err := h.Reload()
+
+		if err != nil {
+			log.Printf("Keeping the previous redirect table: %s", err)
+		} else if reloaded {
+			log.Printf("Reloaded the redirect table from %s", h.path)
+		}
+
+	}
+
+}
@@ -0,0 +1,110 @@
+package urlshort
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestReloadingHandler(t *testing.T) {
+
+	path := filepath.Join(t.TempDir(), "links.yaml")
+
+	modTime := time.Now().Add(-time.Hour)
+
+	write := func(data string) {
+		ioutil.WriteFile(path, []byte(data), 0644)
+		modTime = modTime.Add(time.Second)
+		os.Chtimes(path, modTime, modTime)
+	}
+
+	write("- path: /a\n  url: https://a.example\n")
+
+	h, err := NewReloadingHandler(path, notFound, Strict())
+
+	if err != nil {
+		t.Fatalf("NewReloadingHandler() returned an error: %s", err)
+	}
+
+	checkRedirect(t, h, "/a", "https://a.example")
+
+	if reloaded, err := h.Reload(); reloaded || err != nil {
+		t.Errorf("Got %t, %v for an unchanged file, want false, nil", reloaded, err)
+	}
+
+	write("- path: /b\n  url: https://b.example\n")
+
+	if reloaded, err := h.Reload(); !reloaded || err != nil {
+		t.Errorf("Got %t, %v for a changed file, want true, nil", reloaded, err)
+	}
+
+	checkRedirect(t, h, "/a", "")
+	checkRedirect(t, h, "/b", "https://b.example")
+
+	for _, data := range []string{"- path: [", "- path: /c\n", ""} {
+
+		write(data)
+
+		if reloaded, err := h.Reload(); reloaded || err == nil {
+			t.Errorf("Got %t, %v for %q, want false and an error", reloaded, err, data)
+		}
+
+		checkRedirect(t, h, "/b", "https://b.example")
+
+		// A rejected file is only reported once, until it changes again
+
+		if reloaded, err := h.Reload(); reloaded || err != nil {
+			t.Errorf("Got %t, %v for an unchanged bad file, want false, nil", reloaded, err)
+		}
+
+	}
+
+}
+
+func TestReloadingHandlerWarnOnly(t *testing.T) {
+
+	path := filepath.Join(t.TempDir(), "links.yaml")
+
+	ioutil.WriteFile(path, []byte("- path: a\n  url: https://a.example\n- path: /b\n  url: https://b.example\n"), 0644)
+
+	var warnings []error
+
+	h, err := NewReloadingHandler(path, notFound, WithWarn(func(err error) {
+		warnings = append(warnings, err)
+	}))
+
+	if err != nil {
+		t.Fatalf("NewReloadingHandler() returned an error in warn-only mode: %s", err)
+	}
+
+	checkRedirect(t, h, "/b", "https://b.example")
+
+	if len(warnings) != 1 {
+		t.Errorf("Got %d warnings, want 1", len(warnings))
+	}
+
+	if _, err := NewReloadingHandler(path, notFound, Strict()); err == nil {
+		t.Errorf("Expected an error in strict mode")
+	}
+
+	// Once serving, a file with problems is rejected even in warn-only mode
+
+	modTime := time.Now().Add(time.Hour)
+
+	ioutil.WriteFile(path, []byte("- path: /b\n  url: https://c.example\n- path: /b\n  url: https://d.example\n"), 0644)
+
+	os.Chtimes(path, modTime, modTime)
+
+	if reloaded, err := h.Reload(); reloaded || err == nil {
+		t.Errorf("Got %t, %v for an invalid reload, want false and an error", reloaded, err)
+	}
+
+	checkRedirect(t, h, "/b", "https://b.example")
+
+	if len(warnings) != 1 {
+		t.Errorf("Got %d warnings, want the rejected reload reported as an error instead", len(warnings))
+	}
+
+}
@@ -0,0 +1,377 @@
+package urlshort
+
+import (
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// A table holds entries keyed by host and path. Entries without a host (or
+// with the wildcard host "*") apply to every host, unless that host has an
+// entry of its own for the path.
+//
+// Each host's entries are kept in a trie keyed by path segment. Paths may
+// also be patterns, in which a segment beginning with ':' captures a single
+// segment of the request path, and a final '*' segment captures the rest of
+// it. Captures are substituted into the entry's URL by name (e.g. ":user"
+// or "*"), escaped for the part of the URL they appear in. A path whose
+// captures hold "." or ".." segments doesn't match a pattern at all.
+//
+// A table is never changed once it has been built, so it is safe to look
+// up paths from many goroutines at once.
+type table struct {
+	hosts map[string]*node
+}
+
+// node is a single segment in a trie of paths.
+type node struct {
+	// literals holds the children for literal segments
+	literals map[string]*node
+	// param is the child for a ':name' segment, shared by every name
+	param *node
+	// entry is the entry for a path that ends at this node
+	entry *leaf
+	// wildcard is the entry for a path that ends in '*' after this node
+	wildcard *leaf
+}
+
+// leaf is an entry in a trie, along with the names of the segments that it
+// captures, in order.
+type leaf struct {
+	pathURL
+	names []string
+	// parts is the entry's URL, split around the captures it refers to
+	parts []part
+}
+
+// part is either literal text from a URL, or the index of a capture to be
+// substituted into it, escaped for the URL's path or (if query is true) its
+// query string.
+type part struct {
+	text    string
+	capture int
+	query   bool
+}
+
+var capture = regexp.MustCompile(`:([A-Za-z_][A-Za-z0-9_]*)|\*`)
+
+func newTable(pathUrls []pathURL) *table {
+
+	t := &table{hosts: map[string]*node{}}
+
+	for _, pu := range pathUrls {
+
+		// Request paths always begin with a slash, so an entry without
+		// one could never match
+
+		if !strings.HasPrefix(pu.Path, "/") {
+			continue
+		}
+
+		host := hostname(pu.Host)
+
+		root, ok := t.hosts[host]
+
+		if !ok {
+			root = &node{}
+			t.hosts[host] = root
+		}
+
+		root.insert(pu)
+
+	}
+
+	return t
+
+}
+
+// insert adds an entry beneath the node, replacing any entry with the same
+// path (or the same pattern, ignoring the names of its captures).
+func (n *node) insert(pu pathURL) {
+
+	segments := strings.Split(pu.Path[1:], "/")
+
+	l := &leaf{pathURL: pu}
+
+	isPattern := isPattern(pu.Path)
+
+	for i, s := range segments {
+
+		if isPattern && s == "*" && i == len(segments)-1 {
+			l.names = append(l.names, "*")
+			l.compile()
+			n.wildcard = l
+			return
+		}
+
+		if isPattern && strings.HasPrefix(s, ":") {
+
+			l.names = append(l.names, s[1:])
+
+			if n.param == nil {
+				n.param = &node{}
+			}
+
+			n = n.param
+
+			continue
+
+		}
+
+		child, ok := n.literals[s]
+
+		if !ok {
+
+			if n.literals == nil {
+				n.literals = map[string]*node{}
+			}
+
+			child = &node{}
+			n.literals[s] = child
+
+		}
+
+		n = child
+
+	}
+
+	l.compile()
+
+	n.entry = l
+
+}
+
+// compile splits the leaf's URL around the captures it refers to, so that
+// they can be substituted without searching the URL on every lookup.
+func (l *leaf) compile() {
+
+	if len(l.names) == 0 {
+		return
+	}
+
+	index := make(map[string]int, len(l.names))
+
+	for i, name := range l.names {
+		index[name] = i
+	}
+
+	start := 0
+
+	query := strings.IndexByte(l.URL, '?')
+
+	if query < 0 {
+		query = len(l.URL)
+	}
+
+	for _, m := range capture.FindAllStringSubmatchIndex(l.URL, -1) {
+
+		name := "*"
+
+		if m[2] >= 0 {
+			name = l.URL[m[2]:m[3]]
+		}
+
+		i, ok := index[name]
+
+		if !ok {
+			continue
+		}
+
+		l.parts = append(l.parts, part{text: l.URL[start:m[0]], capture: -1}, part{capture: i, query: m[0] > query})
+
+		start = m[1]
+
+	}
+
+	l.parts = append(l.parts, part{text: l.URL[start:], capture: -1})
+
+}
+
+func isPattern(path string) bool {
+	return strings.Contains(path, "/:") || strings.HasSuffix(path, "/*")
+}
+
+// hostname normalizes a host for matching, dropping any port. The wildcard
+// host "*" is the same as no host at all.
+func hostname(host string) string {
+
+	if host == "*" {
+		return ""
+	}
+
+	return strings.ToLower((&url.URL{Host: host}).Hostname())
+
+}
+
+// lookup returns the entry for a path on a host, with any captures
+// substituted into its URL. The host's own entries are tried before the
+// wildcard host's. Within each, segments are matched from left to right,
+// with literal segments tried before parameters and parameters before a
+// wildcard, so exact paths win over patterns and more specific patterns
+// win over less specific ones.
+func (t *table) lookup(host, path string) (pathURL, bool) {
+
+	if !strings.HasPrefix(path, "/") {
+		return pathURL{}, false
+	}
+
+	for _, h := range []string{hostname(host), ""} {
+
+		if root, ok := t.hosts[h]; ok {
+
+			// A capture that climbs out of its directory is rejected
+			// rather than passed on to the target
+
+			if l, captures := root.lookup(path[1:], false, nil); l != nil && !traverses(captures) {
+				return l.expand(captures), true
+			}
+
+		}
+
+		if h == "" {
+			break
+		}
+
+	}
+
+	return pathURL{}, false
+
+}
+
+// lookup matches the rest of a path beneath the node, returning the entry
+// that it matches along with the captured segments. If end is true, the
+// whole path has already been matched.
+func (n *node) lookup(path string, end bool, captures []string) (*leaf, []string) {
+
+	if end {
+
+		if n.entry != nil {
+			return n.entry, captures
+		}
+
+		if n.wildcard != nil {
+			return n.wildcard, append(captures, "")
+		}
+
+		return nil, nil
+
+	}
+
+	segment, rest, last := path, "", true
+
+	if i := strings.IndexByte(path, '/'); i >= 0 {
+		segment, rest, last = path[:i], path[i+1:], false
+	}
+
+	if child, ok := n.literals[segment]; ok {
+
+		if l, c := child.lookup(rest, last, captures); l != nil {
+			return l, c
+		}
+
+	}
+
+	if n.param != nil && segment != "" {
+
+		if l, c := n.param.lookup(rest, last, append(captures, segment)); l != nil {
+			return l, c
+		}
+
+	}
+
+	if n.wildcard != nil {
+		return n.wildcard, append(captures, path)
+	}
+
+	return nil, nil
+
+}
+
+// traverses reports whether any capture holds a "." or ".." segment.
+func traverses(captures []string) bool {
+
+	for _, c := range captures {
+		for _, segment := range strings.Split(c, "/") {
+			if segment == "." || segment == ".." {
+				return true
+			}
+		}
+	}
+
+	return false
+
+}
+
+// expand returns the leaf's entry with the captured segments substituted
+// into its URL.
+func (l *leaf) expand(captures []string) pathURL {
+
+	pu := l.pathURL
+
+	if len(l.parts) == 0 {
+		return pu
+	}
+
+	var b strings.Builder
+
+	for _, p := range l.parts {
+
+		if p.capture < 0 {
+			b.WriteString(p.text)
+			continue
+		}
+
+		if p.query {
+			b.WriteString(url.QueryEscape(captures[p.capture]))
+			continue
+		}
+
+		for i, segment := range strings.Split(captures[p.capture], "/") {
+
+			if i > 0 {
+				b.WriteByte('/')
+			}
+
+			b.WriteString(url.PathEscape(segment))
+
+		}
+
+	}
+
+	pu.URL = b.String()
+
+	return pu
+
+}
+
+// tableHandler redirects any path found in the table to its URL, calling
+// the fallback for the rest (and for expired entries, unless they are gone).
+func tableHandler(t *table, fallback http.Handler) http.HandlerFunc {
+
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		pu, ok := t.lookup(r.Host, r.URL.Path)
+
+		if ok && pu.expired(time.Now()) {
+
+			if pu.Gone {
+				http.Error(w, "This link has expired.", http.StatusGone)
+				return
+			}
+
+			ok = false
+
+		}
+
+		if ok {
+			pu.redirect(w, r)
+			return
+		}
+
+		fallback.ServeHTTP(w, r)
+
+	}
+
+}
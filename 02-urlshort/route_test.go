@@ -0,0 +1,131 @@
+package urlshort
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestMapHandlerPatterns(t *testing.T) {
+
+	h := MapHandler(map[string]string{
+		"/gh/:user/:repo":   "https://github.com/:user/:repo",
+		"/gh/golang/:repo":  "https://go.googlesource.com/:repo",
+		"/gh/gophercises/x": "https://gophercises.com",
+		"/gh/:user/*":       "https://github.com/:user/*",
+		"/docs/*":           "https://pkg.go.dev/*",
+		"/port/:p":          "http://localhost:8080/:p",
+		"/q/:term":          "https://search.example/?q=:term",
+		"/s/*":              "https://search.example/s/*?path=*",
+	}, notFound)
+
+	tests := []struct {
+		path string
+		url  string
+	}{
+		{"/gh/gophercises/x", "https://gophercises.com"},
+		{"/gh/gophercises/quiz", "https://github.com/gophercises/quiz"},
+		{"/gh/golang/go", "https://go.googlesource.com/go"},
+		{"/gh/gophercises/quiz/tree/main", "https://github.com/gophercises/quiz/tree/main"},
+		{"/gh/gophercises", "https://github.com/gophercises/"},
+		{"/docs/net/http", "https://pkg.go.dev/net/http"},
+		{"/docs", "https://pkg.go.dev/"},
+		{"/docs/a%20b", "https://pkg.go.dev/a%20b"},
+		{"/port/x", "http://localhost:8080/x"},
+		{"/port/x/y", ""},
+		{"/q/x%26admin%3D1", "https://search.example/?q=x%26admin%3D1"},
+		{"/q/a%20b", "https://search.example/?q=a+b"},
+		{"/s/a/b%26c", "https://search.example/s/a/b&c?path=a%2Fb%26c"},
+		{"/docs/..%2f..%2fevil", ""},
+		{"/docs/a/../b", ""},
+		{"/gh/../repo", ""},
+		{"/gh", ""},
+		{"/other", ""},
+	}
+
+	for _, test := range tests {
+		checkRedirect(t, h, test.path, test.url)
+	}
+
+}
+
+func TestTableBacktracking(t *testing.T) {
+
+	tbl := newTable([]pathURL{
+		{Path: "/a/b/c", URL: "https://exact.example"},
+		{Path: "/a/:x/d", URL: "https://param.example/:x"},
+		{Path: "/a/*", URL: "https://wildcard.example/*"},
+		{Path: "/a/", URL: "https://slash.example"},
+	})
+
+	tests := []struct {
+		path string
+		url  string
+	}{
+		{"/a/b/c", "https://exact.example"},
+		{"/a/b/d", "https://param.example/b"},
+		{"/a/b/e", "https://wildcard.example/b/e"},
+		{"/a/", "https://slash.example"},
+		{"/a", "https://wildcard.example/"},
+		{"a", ""},
+	}
+
+	for _, test := range tests {
+
+		pu, ok := tbl.lookup("", test.path)
+
+		if ok != (test.url != "") || pu.URL != test.url {
+			t.Errorf("%s: got %q, %t, want %q", test.path, pu.URL, ok, test.url)
+		}
+
+	}
+
+}
+
+func BenchmarkLookup(b *testing.B) {
+
+	for _, size := range []int{1000, 100000, 1000000} {
+
+		pathUrls := make([]pathURL, size)
+
+		byPath := make(map[string]pathURL, size)
+
+		for i := range pathUrls {
+			pathUrls[i] = pathURL{Path: fmt.Sprintf("/l/%d", i), URL: fmt.Sprintf("https://example.com/%d", i)}
+			byPath[pathUrls[i].Path] = pathUrls[i]
+		}
+
+		tbl := newTable(append(pathUrls, pathURL{Path: "/p/:id/*", URL: "https://example.com/:id/*"}))
+
+		paths := make([]string, 1024)
+
+		for i := range paths {
+			paths[i] = pathUrls[(i*7919)%size].Path
+		}
+
+		b.Run(fmt.Sprintf("map/%d", size), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, ok := byPath[paths[i%len(paths)]]; !ok {
+					b.Fatal("missing path")
+				}
+			}
+		})
+
+		b.Run(fmt.Sprintf("table/%d", size), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, ok := tbl.lookup("", paths[i%len(paths)]); !ok {
+					b.Fatal("missing path")
+				}
+			}
+		})
+
+		b.Run(fmt.Sprintf("table-pattern/%d", size), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, ok := tbl.lookup("", "/p/42/a/b"); !ok {
+					b.Fatal("missing path")
+				}
+			}
+		})
+
+	}
+
+}
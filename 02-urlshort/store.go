@@ -0,0 +1,199 @@
+package urlshort
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrNotFound is returned when deleting a path that isn't in a Store.
+var ErrNotFound = errors.New("no link with the given path")
+
+// ErrExists is returned when adding a path that is already in a Store.
+var ErrExists = errors.New("a link with the given path already exists")
+
+// Link is a short path along with the URL that it redirects to.
+type Link struct {
+	Path    string    `json:"path" yaml:"path"`
+	URL     string    `json:"url" yaml:"url"`
+	Created time.Time `json:"created,omitempty" yaml:"created,omitempty"`
+}
+
+// Store is a table of links that may be changed while it is being served.
+type Store interface {
+	// Lookup returns the link for a path, if there is one
+	Lookup(path string) (Link, bool, error)
+	// Put adds a link, replacing any existing link with the same path
+	Put(link Link) error
+	// Add adds a link, unless there is already a link with the same path
+	Add(link Link) error
+	// Delete removes the link for a path
+	Delete(path string) error
+	// List returns every link, ordered by path
+	List() ([]Link, error)
+}
+
+// StoreHandler will return an http.HandlerFunc that redirects any path
+// found in the store to its URL. If the path is not in the store, then the
+// fallback http.Handler will be called instead.
+func StoreHandler(store Store, fallback http.Handler) http.HandlerFunc {
+
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		link, ok, err := store.Lookup(r.URL.Path)
+
+		if err != nil {
+			log.Printf("%v", err)
+			http.Error(w, "Something went wrong!", http.StatusInternalServerError)
+			return
+		}
+
+		if ok {
+			http.Redirect(w, r, link.URL, http.StatusFound)
+			return
+		}
+
+		fallback.ServeHTTP(w, r)
+
+	}
+
+}
+
+// MemoryStore is a Store (and HitLog) that is kept in memory, and is lost
+// on exit.
+type MemoryStore struct {
+	mu    sync.RWMutex
+	links map[string]Link
+	hits  map[string][]Hit
+}
+
+// NewMemoryStore returns a MemoryStore holding the given mapping of paths
+// to URLs.
+func NewMemoryStore(pathsToUrls map[string]string) *MemoryStore {
+
+	s := &MemoryStore{
+		links: map[string]Link{},
+		hits:  map[string][]Hit{},
+	}
+
+	now := time.Now()
+
+	for path, url := range pathsToUrls {
+		s.links[path] = Link{Path: path, URL: url, Created: now}
+	}
+
+	return s
+
+}
+
+// Lookup returns the link for a path, if there is one.
+func (s *MemoryStore) Lookup(path string) (Link, bool, error) {
+
+	s.mu.RLock()
+
+	defer s.mu.RUnlock()
+
+	link, ok := s.links[path]
+
+	return link, ok, nil
+
+}
+
+// Put adds a link, replacing any existing link with the same path.
+func (s *MemoryStore) Put(link Link) error {
+
+	s.mu.Lock()
+
+	defer s.mu.Unlock()
+
+	s.links[link.Path] = link
+
+	return nil
+
+}
+
+// Add adds a link, unless there is already a link with the same path.
+func (s *MemoryStore) Add(link Link) error {
+
+	s.mu.Lock()
+
+	defer s.mu.Unlock()
+
+	if _, ok := s.links[link.Path]; ok {
+		return ErrExists
+	}
+
+	s.links[link.Path] = link
+
+	return nil
+
+}
+
+// Delete removes the link for a path, along with its hits.
+func (s *MemoryStore) Delete(path string) error {
+
+	s.mu.Lock()
+
+	defer s.mu.Unlock()
+
+	if _, ok := s.links[path]; !ok {
+		return ErrNotFound
+	}
+
+	delete(s.links, path)
+
+	delete(s.hits, path)
+
+	return nil
+
+}
+
+// List returns every link, ordered by path.
+func (s *MemoryStore) List() ([]Link, error) {
+
+	s.mu.RLock()
+
+	defer s.mu.RUnlock()
+
+	links := make([]Link, 0, len(s.links))
+
+	for _, link := range s.links {
+		links = append(links, link)
+	}
+
+	sort.Slice(links, func(i, j int) bool {
+		return links[i].Path < links[j].Path
+	})
+
+	return links, nil
+
+}
+
+// AddHits records a batch of hits.
+func (s *MemoryStore) AddHits(hits []Hit) error {
+
+	s.mu.Lock()
+
+	defer s.mu.Unlock()
+
+	for _, hit := range hits {
+		s.hits[hit.Path] = append(s.hits[hit.Path], hit)
+	}
+
+	return nil
+
+}
+
+// Hits returns the hits on a path, oldest first.
+func (s *MemoryStore) Hits(path string) ([]Hit, error) {
+
+	s.mu.RLock()
+
+	defer s.mu.RUnlock()
+
+	return append([]Hit{}, s.hits[path]...), nil
+
+}
@@ -0,0 +1,147 @@
+package urlshort
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+)
+
+var notFound = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	http.Error(w, "Not found.", http.StatusNotFound)
+})
+
+func testStore(t *testing.T, s Store) {
+
+	if err := s.Put(Link{Path: "/b", URL: "https://b.example"}); err != nil {
+		t.Fatalf("Put() returned an error: %s", err)
+	}
+
+	s.Put(Link{Path: "/a", URL: "https://a.example"})
+	s.Put(Link{Path: "/a", URL: "https://a.example/new"})
+
+	link, ok, err := s.Lookup("/a")
+
+	if err != nil || !ok || link.URL != "https://a.example/new" {
+		t.Errorf("Got %+v, %t, %v, want the replaced link for /a", link, ok, err)
+	}
+
+	if _, ok, _ := s.Lookup("/c"); ok {
+		t.Errorf("Expected no link for /c")
+	}
+
+	links, err := s.List()
+
+	if err != nil || len(links) != 2 || links[0].Path != "/a" || links[1].Path != "/b" {
+		t.Errorf("Got %+v, %v, want /a and /b", links, err)
+	}
+
+	if err := s.Add(Link{Path: "/b", URL: "https://b.example/new"}); err != ErrExists {
+		t.Errorf("Got %v, want ErrExists", err)
+	}
+
+	if link, _, _ := s.Lookup("/b"); link.URL != "https://b.example" {
+		t.Errorf("Got %+v, want the original link for /b", link)
+	}
+
+	if err := s.Add(Link{Path: "/c", URL: "https://c.example"}); err != nil {
+		t.Errorf("Add() returned an error: %s", err)
+	}
+
+	if _, ok, _ := s.Lookup("/c"); !ok {
+		t.Errorf("Expected /c to have been added")
+	}
+
+	s.Delete("/c")
+
+	if err := s.Delete("/a"); err != nil {
+		t.Errorf("Delete() returned an error: %s", err)
+	}
+
+	if err := s.Delete("/a"); err != ErrNotFound {
+		t.Errorf("Got %v, want ErrNotFound", err)
+	}
+
+	if _, ok, _ := s.Lookup("/a"); ok {
+		t.Errorf("Expected /a to have been deleted")
+	}
+
+}
+
+func TestMemoryStore(t *testing.T) {
+	testStore(t, NewMemoryStore(nil))
+}
+
+func TestBoltStore(t *testing.T) {
+
+	path := filepath.Join(t.TempDir(), "links.db")
+
+	s, err := OpenBoltStore(path)
+
+	if err != nil {
+		t.Fatalf("OpenBoltStore() returned an error: %s", err)
+	}
+
+	testStore(t, s)
+
+	s.Close()
+
+	// Links must survive a restart
+
+	s, err = OpenBoltStore(path)
+
+	if err != nil {
+		t.Fatalf("OpenBoltStore() returned an error: %s", err)
+	}
+
+	defer s.Close()
+
+	if _, ok, _ := s.Lookup("/b"); !ok {
+		t.Errorf("Expected /b to survive reopening the store")
+	}
+
+}
+
+func TestStoreHandler(t *testing.T) {
+
+	s := NewMemoryStore(map[string]string{"/a": "https://a.example"})
+
+	h := StoreHandler(s, notFound)
+
+	testCases := []struct {
+		path     string
+		status   int
+		location string
+	}{
+		{"/a", http.StatusFound, "https://a.example"},
+		{"/b", http.StatusNotFound, ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.path, func(t *testing.T) {
+
+			w := httptest.NewRecorder()
+
+			h.ServeHTTP(w, httptest.NewRequest("GET", tc.path, nil))
+
+			if w.Code != tc.status || w.Header().Get("Location") != tc.location {
+				t.Errorf("Got %d %q, want %d %q", w.Code, w.Header().Get("Location"), tc.status, tc.location)
+			}
+
+		})
+	}
+
+	// Changes to the store take effect immediately
+
+	s.Put(Link{Path: "/b", URL: "https://b.example"})
+
+	w := httptest.NewRecorder()
+
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/b", nil))
+
+	if got := fmt.Sprint(w.Code, " ", w.Header().Get("Location")); got != "302 https://b.example" {
+		t.Errorf("Got %s, want a redirect to https://b.example", got)
+	}
+
+}
@@ -0,0 +1,172 @@
+package urlshort
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// ValidationError lists every problem found in a table of links.
+type ValidationError []error
+
+func (e ValidationError) Error() string {
+
+	lines := make([]string, len(e))
+
+	for i, err := range e {
+		lines[i] = err.Error()
+	}
+
+	return fmt.Sprintf("%d problem(s) with links:\n%s", len(e), strings.Join(lines, "\n"))
+
+}
+
+// Unwrap returns the individual problems.
+func (e ValidationError) Unwrap() []error {
+	return e
+}
+
+// validate checks a table for missing fields, duplicate paths on the same
+// host, paths without a leading slash, URLs that are neither http(s) nor a
+// local path, and redirects that cycle through local paths. It returns a
+// ValidationError if there are any problems.
+func validate(pathUrls []pathURL) error {
+
+	var errs ValidationError
+
+	first := map[string]int{}
+
+	for i, pu := range pathUrls {
+
+		problem := func(format string, args ...interface{}) {
+			errs = append(errs, fmt.Errorf("entry %d (%s): %s", i+1, pu.key(), fmt.Sprintf(format, args...)))
+		}
+
+		switch {
+		case pu.Path == "":
+			problem("missing a path")
+		case !strings.HasPrefix(pu.Path, "/"):
+			problem("path must begin with '/'")
+		}
+
+		if j, ok := first[pu.key()]; ok && pu.Path != "" {
+			problem("duplicates the path of entry %d", j+1)
+		} else {
+			first[pu.key()] = i
+		}
+
+		if pu.URL == "" {
+			problem("missing a url")
+		} else if u, err := url.Parse(pu.URL); err != nil {
+			problem("invalid url: %s", err)
+		} else if _, ok := localPath(pu.URL); !ok && ((u.Scheme != "http" && u.Scheme != "https") || u.Host == "") {
+			problem("url must be an http(s) URL or a local path")
+		}
+
+	}
+
+	errs = append(errs, findCycles(pathUrls)...)
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+
+}
+
+// key identifies an entry by its host (if it has one) and path.
+func (pu pathURL) key() string {
+	return hostname(pu.Host) + pu.Path
+}
+
+// localPath returns the path that a URL points to, if the URL is a path on
+// this server rather than an absolute URL.
+func localPath(rawURL string) (string, bool) {
+
+	u, err := url.Parse(rawURL)
+
+	if err != nil || u.Scheme != "" || u.Host != "" || !strings.HasPrefix(u.Path, "/") {
+		return "", false
+	}
+
+	return u.Path, true
+
+}
+
+// findCycles follows the redirects from each entry through local paths,
+// reporting each loop once.
+func findCycles(pathUrls []pathURL) []error {
+
+	var errs []error
+
+	t := newTable(pathUrls)
+
+	reported := map[string]bool{}
+
+	for _, pu := range pathUrls {
+
+		if isPattern(pu.Path) {
+			continue
+		}
+
+		var chain []string
+
+		seen := map[string]bool{}
+
+		for path := pu.Path; ; {
+
+			if seen[path] {
+
+				if path == pu.Path {
+
+					key := cycleKey(chain)
+
+					if !reported[key] {
+						reported[key] = true
+						errs = append(errs, fmt.Errorf("redirect cycle: %s -> %s", strings.Join(chain, " -> "), path))
+					}
+
+				}
+
+				break
+
+			}
+
+			seen[path] = true
+
+			chain = append(chain, path)
+
+			entry, ok := t.lookup(pu.Host, path)
+
+			if !ok {
+				break
+			}
+
+			if path, ok = localPath(entry.URL); !ok {
+				break
+			}
+
+		}
+
+	}
+
+	return errs
+
+}
+
+// cycleKey identifies a cycle regardless of which of its paths it was
+// found from.
+func cycleKey(chain []string) string {
+
+	start := 0
+
+	for i, path := range chain {
+		if path < chain[start] {
+			start = i
+		}
+	}
+
+	return strings.Join(append(append([]string{}, chain[start:]...), chain[:start]...), " ")
+
+}
@@ -0,0 +1,87 @@
+package urlshort
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+
+	yml := `
+- path: /a
+  url: https://a.example
+- path: /a
+  url: https://a.example/again
+- path: b
+  url: https://b.example
+- path: /c
+  url: ftp://c.example
+- path: /d
+  url: "https://d.example/%zz"
+- path: /e
+- path: /loop1
+  url: /loop2
+- path: /loop2
+  url: /loop3?x=1
+- path: /loop3
+  url: /loop1
+- path: /self
+  url: /self
+- path: /ok
+  url: /a
+- path: /gh/:user
+  url: /gh/:user
+`
+
+	h, err := YAMLHandler([]byte(yml), notFound, Strict())
+
+	var verr ValidationError
+
+	if h != nil || !errors.As(err, &verr) {
+		t.Fatalf("Got %v, want a ValidationError", err)
+	}
+
+	want := []string{
+		"entry 2 (/a): duplicates the path of entry 1",
+		"entry 3 (b): path must begin with '/'",
+		"entry 4 (/c): url must be an http(s) URL or a local path",
+		"entry 5 (/d): invalid url",
+		"entry 6 (/e): missing a url",
+		"redirect cycle: /loop1 -> /loop2 -> /loop3 -> /loop1",
+		"redirect cycle: /self -> /self",
+	}
+
+	if len(verr) != len(want) {
+		t.Errorf("Got %d problems, want %d:\n%s", len(verr), len(want), err)
+	}
+
+	for i := 0; i < len(verr) && i < len(want); i++ {
+		if !strings.HasPrefix(verr[i].Error(), want[i]) {
+			t.Errorf("Got %q, want %q", verr[i], want[i])
+		}
+	}
+
+}
+
+func TestValidateWarnOnly(t *testing.T) {
+
+	var warnings []error
+
+	h, err := YAMLHandler([]byte("- path: a\n  url: https://a.example\n"), notFound, WithWarn(func(err error) {
+		warnings = append(warnings, err)
+	}))
+
+	if h == nil || err != nil {
+		t.Fatalf("Got %v, want a handler in warn-only mode", err)
+	}
+
+	if len(warnings) != 1 {
+		t.Errorf("Got %d warnings, want 1", len(warnings))
+	}
+
+	if _, err := YAMLHandler([]byte("- path: /a\n  url: https://a.example\n"), notFound, Strict()); err != nil {
+		t.Errorf("YAMLHandler() returned an error for a valid table: %s", err)
+	}
+
+}